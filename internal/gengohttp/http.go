@@ -0,0 +1,225 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gengohttp contains the HTTP/JSON handler code generator.
+package gengohttp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/gohttp"
+)
+
+const (
+	contextPackage   = protogen.GoImportPath("context")
+	ioPackage        = protogen.GoImportPath("io")
+	httpPackage      = protogen.GoImportPath("net/http")
+	protoPackage     = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	protojsonPackage = protogen.GoImportPath("google.golang.org/protobuf/encoding/protojson")
+)
+
+// Options controls the generated HTTP handlers.
+type Options struct {
+	// DeclareServer specifies whether to declare the <Service>Server interface
+	// that the handlers call. It is false if another plugin, such as grpc or ctx,
+	// declares an interface of that name with the same methods.
+	DeclareServer bool
+}
+
+// route is the HTTP method and path that a proto method is served at.
+type route struct {
+	method, path string
+}
+
+// GenerateFileContent generates the HTTP handlers of the services in file,
+// excluding the package statement.
+func GenerateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, opts Options) error {
+	routes := make(map[*protogen.Method]route)
+	for _, service := range file.Services {
+		seen := make(map[string]*protogen.Method)
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				return fmt.Errorf("protoc-gen-go: %v: method %v is streaming, which the http plugin does not support",
+					file.Desc.Path(), method.Desc.FullName())
+			}
+			r, err := methodRoute(method)
+			if err != nil {
+				return fmt.Errorf("protoc-gen-go: %v: %v", file.Desc.Path(), err)
+			}
+			if m, ok := seen[r.path]; ok {
+				return fmt.Errorf("protoc-gen-go: %v: methods %v and %v are both routed to %v",
+					file.Desc.Path(), m.Desc.FullName(), method.Desc.FullName(), r.path)
+			}
+			seen[r.path] = method
+			routes[method] = r
+		}
+	}
+	for _, service := range file.Services {
+		if len(service.Methods) == 0 {
+			continue
+		}
+		genService(g, service, routes, opts)
+	}
+	return nil
+}
+
+// methodRoute returns the route of method given by its (gohttp.route) option,
+// which defaults to "POST /package.Service/Method".
+func methodRoute(method *protogen.Method) (route, error) {
+	r := route{
+		method: "POST",
+		path:   "/" + string(method.Parent.Desc.FullName()) + "/" + string(method.Desc.Name()),
+	}
+
+	s := proto.GetExtension(method.Desc.Options(), gohttp.E_Route).(string)
+	if s == "" {
+		return r, nil
+	}
+
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		r.method, s = s[:i], strings.TrimLeft(s[i+1:], " ")
+		if !isHTTPMethod(r.method) {
+			return route{}, fmt.Errorf("%v: invalid HTTP method %q in (gohttp.route) option", method.Desc.FullName(), r.method)
+		}
+	}
+	if !strings.HasPrefix(s, "/") || strings.ContainsAny(s, " \t?#") {
+		return route{}, fmt.Errorf("%v: invalid path %q in (gohttp.route) option", method.Desc.FullName(), s)
+	}
+	// The path is registered with http.ServeMux as a pattern, in which
+	// braces denote wildcards and a trailing slash matches a whole subtree.
+	if strings.ContainsAny(s, "{}") || strings.HasSuffix(s, "/") {
+		return route{}, fmt.Errorf("%v: path %q in (gohttp.route) option is not matched exactly by http.ServeMux", method.Desc.FullName(), s)
+	}
+	r.path = s
+	return r, nil
+}
+
+func isHTTPMethod(s string) bool {
+	switch s {
+	case "GET", "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+func genService(g *protogen.GeneratedFile, service *protogen.Service, routes map[*protogen.Method]route, opts Options) {
+	serverType := service.GoName + "Server"
+	optionsType := service.GoName + "HTTPOptions"
+	handlerFunc := "New" + service.GoName + "HTTPHandler"
+
+	// Server interface, unless declared by the grpc or ctx plugin.
+	if opts.DeclareServer {
+		g.P("// ", serverType, " is the server API for ", service.GoName, " service.")
+		g.P("type ", serverType, " interface {")
+		for _, method := range service.Methods {
+			g.P(method.GoName, "(", contextPackage.Ident("Context"), ", *", method.Input.GoIdent, ") (*", method.Output.GoIdent, ", error)")
+		}
+		g.P("}")
+		g.P()
+	}
+
+	// Handler options.
+	g.P("// ", optionsType, " configures the handler returned by ", handlerFunc, ".")
+	g.P("type ", optionsType, " struct {")
+	g.P("// MarshalOptions encodes the responses.")
+	g.P("MarshalOptions ", protojsonPackage.Ident("MarshalOptions"))
+	g.P("// UnmarshalOptions decodes the requests.")
+	g.P("UnmarshalOptions ", protojsonPackage.Ident("UnmarshalOptions"))
+	g.P("// MaxRequestBytes limits the size of request bodies; larger requests are")
+	g.P("// rejected with status 413. If zero, the limit is 4 MiB. If negative,")
+	g.P("// request bodies are not limited.")
+	g.P("MaxRequestBytes int64")
+	g.P("// ErrorHandler writes the response for an error returned by the server.")
+	g.P("// If nil, the error message is written with status 500.")
+	g.P("ErrorHandler func(", httpPackage.Ident("ResponseWriter"), ", *", httpPackage.Ident("Request"), ", error)")
+	g.P("}")
+	g.P()
+
+	// Handler.
+	g.P("// ", handlerFunc, " returns an ", httpPackage.Ident("Handler"), " serving the methods of srv")
+	g.P("// with JSON requests and responses. A nil opts uses the default options.")
+	g.P("//")
+	g.P("// The methods are served at:")
+	g.P("//")
+	for _, method := range service.Methods {
+		r := routes[method]
+		g.P("//\t", r.method, " ", r.path)
+	}
+	g.P("func ", handlerFunc, "(srv ", serverType, ", opts *", optionsType, ") ", httpPackage.Ident("Handler"), " {")
+	g.P("if opts == nil {")
+	g.P("opts = &", optionsType, "{}")
+	g.P("}")
+	g.P("mux := ", httpPackage.Ident("NewServeMux"), "()")
+	for _, method := range service.Methods {
+		r := routes[method]
+		g.P("mux.HandleFunc(", strconv.Quote(r.path), ", func(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ") {")
+		g.P("req := new(", method.Input.GoIdent, ")")
+		g.P("if !opts.readRequest(w, r, ", strconv.Quote(r.method), ", req) {")
+		g.P("return")
+		g.P("}")
+		g.P("res, err := srv.", method.GoName, "(r.Context(), req)")
+		g.P("opts.writeResponse(w, r, res, err)")
+		g.P("})")
+	}
+	g.P("return mux")
+	g.P("}")
+	g.P()
+
+	// Request decoding.
+	g.P("// readRequest decodes the body of r into req. An empty body leaves req empty.")
+	g.P("// It replies with an error and returns false if the request is invalid.")
+	g.P("func (o *", optionsType, ") readRequest(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ", method string, req ", protoPackage.Ident("Message"), ") bool {")
+	g.P("if r.Method != method {")
+	g.P(`w.Header().Set("Allow", method)`)
+	g.P(httpPackage.Ident("Error"), "(w, ", httpPackage.Ident("StatusText"), "(", httpPackage.Ident("StatusMethodNotAllowed"), "), ", httpPackage.Ident("StatusMethodNotAllowed"), ")")
+	g.P("return false")
+	g.P("}")
+	g.P("body := r.Body")
+	g.P("limit := o.MaxRequestBytes")
+	g.P("if limit == 0 {")
+	g.P("limit = 4 << 20")
+	g.P("}")
+	g.P("if limit > 0 {")
+	g.P("body = ", httpPackage.Ident("MaxBytesReader"), "(w, body, limit)")
+	g.P("}")
+	g.P("b, err := ", ioPackage.Ident("ReadAll"), "(body)")
+	g.P("if err != nil && limit > 0 && int64(len(b)) >= limit {")
+	g.P(httpPackage.Ident("Error"), "(w, err.Error(), ", httpPackage.Ident("StatusRequestEntityTooLarge"), ")")
+	g.P("return false")
+	g.P("}")
+	g.P("if err == nil && len(b) > 0 {")
+	g.P("err = o.UnmarshalOptions.Unmarshal(b, req)")
+	g.P("}")
+	g.P("if err != nil {")
+	g.P(httpPackage.Ident("Error"), "(w, err.Error(), ", httpPackage.Ident("StatusBadRequest"), ")")
+	g.P("return false")
+	g.P("}")
+	g.P("return true")
+	g.P("}")
+	g.P()
+
+	// Response encoding.
+	g.P("// writeResponse encodes res as the response, or replies with err if it is not nil.")
+	g.P("func (o *", optionsType, ") writeResponse(w ", httpPackage.Ident("ResponseWriter"), ", r *", httpPackage.Ident("Request"), ", res ", protoPackage.Ident("Message"), ", err error) {")
+	g.P("var b []byte")
+	g.P("if err == nil {")
+	g.P("b, err = o.MarshalOptions.Marshal(res)")
+	g.P("}")
+	g.P("if err != nil {")
+	g.P("if o.ErrorHandler != nil {")
+	g.P("o.ErrorHandler(w, r, err)")
+	g.P("return")
+	g.P("}")
+	g.P(httpPackage.Ident("Error"), "(w, err.Error(), ", httpPackage.Ident("StatusInternalServerError"), ")")
+	g.P("return")
+	g.P("}")
+	g.P(`w.Header().Set("Content-Type", "application/json")`)
+	g.P("w.Write(b)")
+	g.P("}")
+	g.P()
+}
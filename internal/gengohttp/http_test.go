@@ -0,0 +1,153 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gengohttp
+
+import (
+	"strings"
+	"testing"
+
+	gengo "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+	"github.com/golang/protobuf/protobuf/types/pluginpb"
+	"github.com/golang/protobuf/protoc-gen-go/gohttp"
+)
+
+func TestHandlers(t *testing.T) {
+	fd := testServiceFile()
+	fd.Service[0].Method = append(fd.Service[0].Method, withRoute(&descriptorpb.MethodDescriptorProto{
+		Name:       proto.String("ListUsers"),
+		InputType:  proto.String(".api.user.GetUserRequest"),
+		OutputType: proto.String(".api.user.GetUserResponse"),
+	}, "GET /v1/users"))
+
+	got, err := generateTestFile(t, fd, Options{DeclareServer: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"type UserServiceServer interface {\n\tGetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)",
+		"func NewUserServiceHTTPHandler(srv UserServiceServer, opts *UserServiceHTTPOptions) http.Handler {",
+		"MarshalOptions protojson.MarshalOptions",
+		`mux.HandleFunc("/api.user.UserService/GetUser", func(w http.ResponseWriter, r *http.Request) {`,
+		`if !opts.readRequest(w, r, "POST", req) {`,
+		"res, err := srv.GetUser(r.Context(), req)",
+		`mux.HandleFunc("/v1/users", func(w http.ResponseWriter, r *http.Request) {`,
+		`if !opts.readRequest(w, r, "GET", req) {`,
+		"body = http.MaxBytesReader(w, body, limit)",
+		"http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)",
+		"err = o.UnmarshalOptions.Unmarshal(b, req)",
+		"b, err = o.MarshalOptions.Marshal(res)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+	if strings.Contains(got, "HTTPServer") {
+		t.Errorf("generated code declares a separate HTTP server interface")
+	}
+
+	got, err = generateTestFile(t, fd, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "type UserServiceServer interface") {
+		t.Errorf("generated code declares UserServiceServer without DeclareServer")
+	}
+}
+
+func TestInvalidRoutes(t *testing.T) {
+	for _, tt := range []struct {
+		route, want string
+	}{
+		{"/v1/users", ""},
+		{"v1/users", `invalid path "v1/users"`},
+		{"FETCH /v1/users", `invalid HTTP method "FETCH"`},
+		{"GET /v1/users?all", `invalid path "/v1/users?all"`},
+		{"GET /v1/users/{id}", `path "/v1/users/{id}" in (gohttp.route) option is not matched exactly`},
+		{"/v1/users/", `path "/v1/users/" in (gohttp.route) option is not matched exactly`},
+		{"/", `path "/" in (gohttp.route) option is not matched exactly`},
+		{"/api.user.UserService/GetUser", "both routed to /api.user.UserService/GetUser"},
+	} {
+		fd := testServiceFile()
+		fd.Service[0].Method = append(fd.Service[0].Method, withRoute(&descriptorpb.MethodDescriptorProto{
+			Name:       proto.String("ListUsers"),
+			InputType:  proto.String(".api.user.GetUserRequest"),
+			OutputType: proto.String(".api.user.GetUserResponse"),
+		}, tt.route))
+		_, err := generateTestFile(t, fd, Options{DeclareServer: true})
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("route %q: unexpected error: %v", tt.route, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("route %q: error = %v, want error containing %q", tt.route, err, tt.want)
+		}
+	}
+}
+
+func TestStreamingMethods(t *testing.T) {
+	fd := testServiceFile()
+	fd.Service[0].Method[0].ServerStreaming = proto.Bool(true)
+	_, err := generateTestFile(t, fd, Options{DeclareServer: true})
+	if want := "method api.user.UserService.GetUser is streaming"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("GenerateFileContent() = %v, want error containing %q", err, want)
+	}
+}
+
+// withRoute sets the (gohttp.route) option of md.
+func withRoute(md *descriptorpb.MethodDescriptorProto, route string) *descriptorpb.MethodDescriptorProto {
+	md.Options = &descriptorpb.MethodOptions{}
+	proto.SetExtension(md.Options, gohttp.E_Route, route)
+	return md
+}
+
+func testServiceFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("api/user.proto"),
+		Package: proto.String("api.user"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/api/user")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("GetUserRequest")},
+			{Name: proto.String("GetUserResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("GetUser"),
+				InputType:  proto.String(".api.user.GetUserRequest"),
+				OutputType: proto.String(".api.user.GetUserResponse"),
+			}},
+		}},
+	}
+}
+
+// generateTestFile generates the .pb.go file for fd including the HTTP
+// handlers and returns the generated source.
+func generateTestFile(t *testing.T, fd *descriptorpb.FileDescriptorProto, opts Options) (string, error) {
+	t.Helper()
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fd.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := gen.FilesByPath[fd.GetName()]
+	g := gengo.GenerateFile(gen, f)
+	if err := GenerateFileContent(gen, f, g, opts); err != nil {
+		return "", err
+	}
+	b, err := g.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,454 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gengokite contains the kite RPC code generator.
+package gengokite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+)
+
+const (
+	contextPackage  = protogen.GoImportPath("context")
+	errorsPackage   = protogen.GoImportPath("errors")
+	fmtPackage      = protogen.GoImportPath("fmt")
+	stringsPackage  = protogen.GoImportPath("strings")
+	kitedescPackage = protogen.GoImportPath("github.com/golang/protobuf/kitedesc")
+)
+
+// Options controls the generated kite code.
+type Options struct {
+	// KitePackage is the import path of the kite rpc runtime.
+	KitePackage protogen.GoImportPath
+	// PBPackage is the import path of the kite service dispatch package.
+	PBPackage protogen.GoImportPath
+	// CodecPackage is the import path of the package providing the
+	// Marshal and Unmarshal functions for messages.
+	// If empty, PBPackage is used.
+	CodecPackage protogen.GoImportPath
+
+	// Context specifies whether a context.Context is passed through
+	// client calls and server handlers.
+	Context bool
+	// Metadata specifies whether to generate a kitedesc.ServiceDesc
+	// describing each service and to register it with kitedesc.
+	Metadata bool
+
+	// Key selects the key that services are registered and called under.
+	Key KeyMode
+	// LegacyKeyAlias specifies whether services keyed by KeyService are also
+	// registered under their KeyFile key, so that clients generated before
+	// switching keys can still call them.
+	LegacyKeyAlias bool
+}
+
+// KeyMode selects the key that kite services are registered and called under.
+// It implements flag.Value so that a mode can be bound to a plugin parameter.
+type KeyMode int
+
+const (
+	// KeyFile keys services by the name of their .proto file.
+	// This is the historical behavior; see protoName.
+	KeyFile KeyMode = iota
+	// KeyService keys services by the full name of their proto service
+	// (e.g., "pkg.Service"), which does not change when a file is renamed
+	// or moved and is unique across directories.
+	KeyService
+)
+
+// String returns the parameter spelling of m.
+func (m KeyMode) String() string {
+	switch m {
+	case KeyFile:
+		return "file"
+	case KeyService:
+		return "service"
+	}
+	return fmt.Sprintf("<unknown:%d>", int(m))
+}
+
+// Set parses a key mode from its parameter spelling.
+func (m *KeyMode) Set(v string) error {
+	switch v {
+	case "file":
+		*m = KeyFile
+	case "service":
+		*m = KeyService
+	default:
+		return fmt.Errorf("invalid kite key mode %q: want file or service", v)
+	}
+	return nil
+}
+
+// DefaultOptions are the options of the historical kite generator.
+var DefaultOptions = Options{
+	KitePackage: "meta/pkg/kite",
+	PBPackage:   "meta/pkg/kite/pb",
+}
+
+// GenerateFileContent generates the kite service definitions, excluding the package statement.
+func GenerateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile, opts Options) error {
+	if opts.LegacyKeyAlias && opts.Key != KeyService {
+		return fmt.Errorf("protoc-gen-go: kite_key_alias requires kite_key=%v", KeyService)
+	}
+	if err := checkMethods(file); err != nil {
+		return err
+	}
+	if opts.CodecPackage == "" {
+		opts.CodecPackage = opts.PBPackage
+	}
+	hasServices := false
+	for _, service := range file.Services {
+		if len(service.Methods) == 0 {
+			continue
+		}
+		genService(file, g, service, opts)
+		hasServices = true
+	}
+	if opts.Metadata && hasServices {
+		return genRawDesc(file, g)
+	}
+	return nil
+}
+
+// checkMethods reports an error for methods which kite cannot serve.
+// The kite runtime only provides unary calls, so streaming methods are
+// rejected instead of being generated as an incorrect unary Invoke.
+func checkMethods(file *protogen.File) error {
+	for _, service := range file.Services {
+		for _, method := range service.Methods {
+			var kind string
+			switch {
+			case method.Desc.IsStreamingClient() && method.Desc.IsStreamingServer():
+				kind = "bidirectional streaming"
+			case method.Desc.IsStreamingClient():
+				kind = "client streaming"
+			case method.Desc.IsStreamingServer():
+				kind = "server streaming"
+			default:
+				continue
+			}
+			return fmt.Errorf("protoc-gen-go: %v: method %v is %v, which the kite runtime does not support",
+				file.Desc.Path(), method.Desc.FullName(), kind)
+		}
+	}
+	return nil
+}
+
+// protoName returns the name that the services of file are registered under
+// with the kite service dispatcher. It is the path of the .proto file without
+// the .proto suffix and without the leading directory.
+func protoName(file *protogen.File) string {
+	var name string
+	if strings.HasSuffix(file.Proto.GetName(), ".proto") {
+		name = strings.TrimSuffix(file.Proto.GetName(), ".proto")
+	}
+	if strings.LastIndex(name, "/") > 0 {
+		name = name[strings.Index(name, "/")+1:]
+	}
+	return name
+}
+
+// serviceKey returns the key that service is registered and called under.
+func serviceKey(file *protogen.File, service *protogen.Service, mode KeyMode) string {
+	if mode == KeyService {
+		return string(service.Desc.FullName())
+	}
+	return protoName(file)
+}
+
+func genService(file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service, opts Options) {
+	serviceName := service.GoName
+	clientName := unexport(serviceName)
+	serverType := serviceName + "Server"
+	implType := serviceName + "Service"
+	key := serviceKey(file, service, opts.Key)
+
+	// ctxParam and ctxArg thread the context through calls if enabled.
+	var ctxParam, ctxArg, ctxType string
+	if opts.Context {
+		ctxType = g.QualifiedGoIdent(contextPackage.Ident("Context"))
+		ctxParam, ctxArg = "ctx "+ctxType+", ", "ctx, "
+		ctxType += ", "
+	}
+
+	// Client instance.
+	g.P("// ", serviceName, " rpc客户端实例")
+	g.P("var ", serviceName, " = &", clientName, "{}")
+	g.P()
+	g.P("type ", clientName, " struct{}")
+	g.P()
+
+	// Client methods.
+	for _, method := range service.Methods {
+		g.P("// ", method.GoName, " 通过destination调用consul rpc服务")
+		g.P("func (c *", clientName, ") ", method.GoName, "(", ctxParam,
+			"destination ", opts.KitePackage.Ident("Destination"),
+			", request *", method.Input.GoIdent,
+			", opts ...", opts.KitePackage.Ident("Option"),
+			") (response *", method.Output.GoIdent, ", err error) {")
+		g.P("reqPBData, err := ", opts.CodecPackage.Ident("Marshal"), "(request)")
+		g.P("if err != nil {")
+		g.P("return nil, ", fmtPackage.Ident("Errorf"), `("request marshal err: %w", err)`)
+		g.P("}")
+		g.P("resPBData, err := ", opts.KitePackage.Ident("Invoke"), "(", ctxArg, "destination, ",
+			strconv.Quote(key), ", ", strconv.Quote(serviceName), ", ", strconv.Quote(method.GoName), ", reqPBData, opts...)")
+		g.P("if err != nil {")
+		g.P("return nil, ", parseErrorFunc(service), "(err)")
+		g.P("}")
+		g.P("response = new(", method.Output.GoIdent, ")")
+		g.P("err = ", opts.CodecPackage.Ident("Unmarshal"), "(resPBData, response)")
+		g.P("return")
+		g.P("}")
+		g.P()
+	}
+
+	// Server interface.
+	g.P("// ", serverType, " is the server API for ", serviceName, " service.")
+	g.P("type ", serverType, " interface {")
+	for _, method := range service.Methods {
+		g.P(method.GoName, "(", ctxType, "*", method.Input.GoIdent, ") (*", method.Output.GoIdent, ", error)")
+	}
+	g.P("}")
+	g.P()
+
+	// Server registration.
+	g.P("type ", implType, " struct {")
+	g.P("handle ", serverType)
+	g.P("}")
+	g.P()
+	g.P("// Reg", serverType, " 注册", serviceName, "服务")
+	g.P("func Reg", serverType, "(handle ", serverType, ") {")
+	if opts.LegacyKeyAlias {
+		g.P("s := &", implType, "{handle: handle}")
+		g.P(opts.PBPackage.Ident("ServiceDispatchObject"), ".AddService(", strconv.Quote(key), ", ", strconv.Quote(serviceName), ", s)")
+		g.P("// Also serve clients which call the service by its legacy file-based key.")
+		g.P(opts.PBPackage.Ident("ServiceDispatchObject"), ".AddService(", strconv.Quote(protoName(file)), ", ", strconv.Quote(serviceName), ", s)")
+	} else {
+		g.P(opts.PBPackage.Ident("ServiceDispatchObject"), ".AddService(", strconv.Quote(key), ", ", strconv.Quote(serviceName), ", &", implType, "{handle: handle})")
+	}
+	g.P("}")
+	g.P()
+
+	genServiceErrors(g, service)
+
+	// Dispatch by function name.
+	errorType := serviceName + "Error"
+	g.P("func (s *", implType, ") Do(", ctxParam, "function string, reqPBData []byte) (resPBData []byte, err error) {")
+	g.P("switch function {")
+	for _, method := range service.Methods {
+		g.P("case ", strconv.Quote(method.GoName), ":")
+		g.P("return s.", method.GoName, "(", ctxArg, "function, reqPBData)")
+	}
+	g.P("default:")
+	g.P("err = &", errorType, "{Code: ", serviceName, "NotFound, Function: function}")
+	g.P("}")
+	g.P("return")
+	g.P("}")
+	g.P()
+
+	// Server handlers.
+	for _, method := range service.Methods {
+		g.P("func (s *", implType, ") ", method.GoName, "(", ctxParam, "function string, reqPBData []byte) (resPBData []byte, err error) {")
+		g.P("req := new(", method.Input.GoIdent, ")")
+		g.P("if err = ", opts.CodecPackage.Ident("Unmarshal"), "(reqPBData, req); err != nil {")
+		g.P("return nil, &", errorType, "{Code: ", serviceName, "BadRequest, Function: function, Err: err}")
+		g.P("}")
+		g.P("res, err := s.handle.", method.GoName, "(", ctxArg, "req)")
+		g.P("if err != nil {")
+		g.P("return nil, &", errorType, "{Code: ", serviceName, "HandlerError, Function: function, Err: err}")
+		g.P("}")
+		g.P("return ", opts.CodecPackage.Ident("Marshal"), "(res)")
+		g.P("}")
+		g.P()
+	}
+
+	if opts.Metadata {
+		genServiceDesc(file, g, service, key)
+	}
+}
+
+// genServiceErrors generates the error type returned by the server stubs
+// of a kite service, along with sentinel errors for each error code.
+//
+// The declarations are prefixed with the service name since several .proto
+// files may contribute to the same Go package.
+func genServiceErrors(g *protogen.GeneratedFile, service *protogen.Service) {
+	name := service.GoName
+	codeType := name + "ErrorCode"
+	errorType := name + "Error"
+
+	g.P("// ", codeType, " classifies the errors returned by the ", name, " server stubs.")
+	g.P("type ", codeType, " int32")
+	g.P()
+	g.P("const (")
+	g.P("// ", name, "NotFound reports a call of a function the service does not provide.")
+	g.P(name, "NotFound ", codeType, " = 1")
+	g.P("// ", name, "BadRequest reports a request that could not be unmarshaled.")
+	g.P(name, "BadRequest ", codeType, " = 2")
+	g.P("// ", name, "HandlerError reports an error returned by the ", name, "Server handler.")
+	g.P(name, "HandlerError ", codeType, " = 3")
+	g.P(")")
+	g.P()
+	g.P("func (c ", codeType, ") String() string {")
+	g.P("switch c {")
+	g.P("case ", name, "NotFound:")
+	g.P(`return "function is not found"`)
+	g.P("case ", name, "BadRequest:")
+	g.P(`return "bad request"`)
+	g.P("case ", name, "HandlerError:")
+	g.P(`return "handler error"`)
+	g.P("}")
+	g.P(`return "unknown error"`)
+	g.P("}")
+	g.P()
+	g.P("// ", errorType, " is an error returned by the ", name, " server stubs")
+	g.P("// and reported to the ", name, " client.")
+	g.P("// Use errors.Is with the Err", name, " sentinel errors to match the error code.")
+	g.P("type ", errorType, " struct {")
+	g.P("Code ", codeType)
+	g.P("Function string")
+	g.P("Err error")
+	g.P("}")
+	g.P()
+	g.P("// Error returns the error message, which is parsed back into a ", errorType)
+	g.P("// by the ", name, " client.")
+	g.P("func (e *", errorType, ") Error() string {")
+	g.P("s := e.Code.String()")
+	g.P(`if e.Function != "" {`)
+	g.P(`s = "`, name, `." + e.Function + ": " + s`)
+	g.P("}")
+	g.P("if e.Err != nil {")
+	g.P(`s += ": " + e.Err.Error()`)
+	g.P("}")
+	g.P("return s")
+	g.P("}")
+	g.P()
+	g.P("func (e *", errorType, ") Unwrap() error {")
+	g.P("return e.Err")
+	g.P("}")
+	g.P()
+	g.P("// Is reports whether target is a ", errorType, " with the same code.")
+	g.P("func (e *", errorType, ") Is(target error) bool {")
+	g.P("t, ok := target.(*", errorType, ")")
+	g.P("return ok && t.Code == e.Code")
+	g.P("}")
+	g.P()
+	g.P("// Sentinel errors for each ", codeType, ".")
+	g.P("var (")
+	for _, code := range []string{"NotFound", "BadRequest", "HandlerError"} {
+		g.P("Err", name, code, " error = &", errorType, "{Code: ", name, code, "}")
+	}
+	g.P(")")
+	g.P()
+
+	// The kite runtime reports the error of a remote call by its message,
+	// so the client recovers the code from the message written by Error.
+	g.P("// ", parseErrorFunc(service), " maps an error returned by the kite runtime back to")
+	g.P("// the ", errorType, " reported by the server stub.")
+	g.P("// Other errors, such as those of the transport, are returned unchanged.")
+	g.P("func ", parseErrorFunc(service), "(err error) error {")
+	g.P("var e *", errorType)
+	g.P("if ", errorsPackage.Ident("As"), "(err, &e) {")
+	g.P("return err")
+	g.P("}")
+	g.P("msg := err.Error()")
+	g.P(`i := `, stringsPackage.Ident("Index"), `(msg, "`, name, `.")`)
+	g.P("if i < 0 {")
+	g.P("return err")
+	g.P("}")
+	g.P("msg = msg[i+len(", strconv.Quote(name+"."), "):]")
+	g.P(`i = `, stringsPackage.Ident("Index"), `(msg, ": ")`)
+	g.P("if i < 0 {")
+	g.P("return err")
+	g.P("}")
+	g.P("function := msg[:i]")
+	g.P("msg = msg[i+len(\": \"):]")
+	g.P("for _, code := range []", codeType, "{", name, "NotFound, ", name, "BadRequest, ", name, "HandlerError} {")
+	g.P("s := code.String()")
+	g.P("switch {")
+	g.P("case msg == s:")
+	g.P("return &", errorType, "{Code: code, Function: function}")
+	g.P(`case `, stringsPackage.Ident("HasPrefix"), `(msg, s+": "):`)
+	g.P("return &", errorType, "{Code: code, Function: function, Err: ", errorsPackage.Ident("New"), "(msg[len(s)+2:])}")
+	g.P("}")
+	g.P("}")
+	g.P("return err")
+	g.P("}")
+	g.P()
+}
+
+// parseErrorFunc returns the name of the generated function which maps
+// the errors of calls of service back to their typed errors.
+func parseErrorFunc(service *protogen.Service) string {
+	return "parse" + service.GoName + "Error"
+}
+
+// genServiceDesc generates the kitedesc.ServiceDesc of a kite service.
+// It is initialized and registered by an init function from the descriptor
+// generated by genRawDesc.
+func genServiceDesc(file *protogen.File, g *protogen.GeneratedFile, service *protogen.Service, key string) {
+	descVar := service.GoName + "_KiteDesc"
+	g.P("// ", descVar, " describes the ", service.GoName, " kite service.")
+	g.P("var ", descVar, " *", kitedescPackage.Ident("ServiceDesc"))
+	g.P()
+	g.P("func init() {")
+	g.P(descVar, " = ", kitedescPackage.Ident("NewServiceDesc"), "(", strconv.Quote(key), ", ", strconv.Quote(service.GoName), ",")
+	g.P(kitedescPackage.Ident("LoadService"), "(", strconv.Quote(string(service.Desc.FullName())), ", ", rawDescVarName(file), "),")
+	for _, method := range service.Methods {
+		g.P(strconv.Quote(method.GoName), ",")
+	}
+	g.P(")")
+	g.P(kitedescPackage.Ident("Register"), "(", descVar, ")")
+	g.P("}")
+	g.P()
+}
+
+// genRawDesc generates the serialized FileDescriptorProto of file that
+// kitedesc.LoadService builds the descriptors of its services from.
+//
+// The descriptor generated for the messages cannot be used instead, since it
+// belongs to the protobuf runtime imported by the generated code, which need
+// not be the one that kitedesc is built against.
+func genRawDesc(file *protogen.File, g *protogen.GeneratedFile) error {
+	descProto := proto.Clone(file.Proto).(*descriptorpb.FileDescriptorProto)
+	descProto.SourceCodeInfo = nil // drop source code information
+	b, err := proto.MarshalOptions{AllowPartial: true, Deterministic: true}.Marshal(descProto)
+	if err != nil {
+		return err
+	}
+
+	g.P("var ", rawDescVarName(file), " = []byte{")
+	for len(b) > 0 {
+		n := 16
+		if n > len(b) {
+			n = len(b)
+		}
+
+		s := ""
+		for _, c := range b[:n] {
+			s += fmt.Sprintf("0x%02x,", c)
+		}
+		g.P(s)
+
+		b = b[n:]
+	}
+	g.P("}")
+	g.P()
+	return nil
+}
+
+// rawDescVarName returns the name of the variable generated by genRawDesc.
+func rawDescVarName(file *protogen.File) string {
+	return unexport(file.GoDescriptorIdent.GoName) + "_kiteRawDesc"
+}
+
+func unexport(s string) string { return strings.ToLower(s[:1]) + s[1:] }
@@ -0,0 +1,444 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gengokite
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/internal/gengokite/testdata/kite/pb"
+	"github.com/golang/protobuf/internal/gengokite/testdata/userpb"
+	gengo "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+	"github.com/golang/protobuf/protobuf/types/pluginpb"
+)
+
+var regenerate = flag.Bool("regenerate", false, "regenerate the golden files")
+
+// testdataPackage is the import path of the testdata directory, which holds
+// stand-ins for the kite runtime and the golden output of goldenServiceFile.
+const testdataPackage = "github.com/golang/protobuf/internal/gengokite/testdata"
+
+func TestImportPaths(t *testing.T) {
+	for _, tt := range []struct {
+		desc            string
+		opts            Options
+		wantIn, wantNot []string
+	}{{
+		desc:   "default kite",
+		opts:   DefaultOptions,
+		wantIn: []string{`"meta/pkg/kite"`, `"meta/pkg/kite/pb"`, "kite.Invoke(destination,", "pb.Marshal(request)", "pb.ServiceDispatchObject.AddService("},
+	}, {
+		desc: "vendored kite with proto codec",
+		opts: Options{
+			KitePackage:  "example.com/vendor/kite",
+			PBPackage:    "example.com/vendor/kite/pb",
+			CodecPackage: "google.golang.org/protobuf/proto",
+		},
+		wantIn:  []string{`"example.com/vendor/kite"`, `"example.com/vendor/kite/pb"`, `"google.golang.org/protobuf/proto"`, "proto.Marshal(request)", "proto.Unmarshal(resPBData, response)"},
+		wantNot: []string{"meta/pkg/kite", "pb.Marshal"},
+	}, {
+		desc: "vendored ctx kite",
+		opts: Options{
+			KitePackage: "example.com/vendor/kite",
+			PBPackage:   "example.com/vendor/kite/pb",
+			Context:     true,
+		},
+		wantIn:  []string{"kite.Invoke(ctx, destination,", "pb.Unmarshal(reqPBData, req)"},
+		wantNot: []string{"meta/pkg/kite"},
+	}} {
+		got := generateTestFile(t, testServiceFile(), tt.opts)
+		for _, want := range tt.wantIn {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: generated code does not contain %s", tt.desc, want)
+			}
+		}
+		for _, want := range tt.wantNot {
+			if strings.Contains(got, want) {
+				t.Errorf("%s: generated code unexpectedly contains %s", tt.desc, want)
+			}
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	opts := DefaultOptions
+	opts.Context = true
+	got := generateTestFile(t, testServiceFile(), opts)
+	for _, want := range []string{
+		"func (c *userService) GetUser(ctx context.Context, destination kite.Destination, request *GetUserRequest, opts ...kite.Option) (response *GetUserResponse, err error) {",
+		"GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)",
+		"func (s *UserServiceService) Do(ctx context.Context, function string, reqPBData []byte) (resPBData []byte, err error) {",
+		"return s.GetUser(ctx, function, reqPBData)",
+		"res, err := s.handle.GetUser(ctx, req)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+}
+
+func TestServiceKey(t *testing.T) {
+	for _, tt := range []struct {
+		desc            string
+		key             KeyMode
+		alias           bool
+		wantIn, wantNot []string
+	}{{
+		desc: "file key",
+		key:  KeyFile,
+		wantIn: []string{
+			`kite.Invoke(destination, "user", "UserService", "GetUser", reqPBData, opts...)`,
+			`pb.ServiceDispatchObject.AddService("user", "UserService", &UserServiceService{handle: handle})`,
+		},
+		wantNot: []string{`"api.user.UserService"`},
+	}, {
+		desc: "service key",
+		key:  KeyService,
+		wantIn: []string{
+			`kite.Invoke(destination, "api.user.UserService", "UserService", "GetUser", reqPBData, opts...)`,
+			`pb.ServiceDispatchObject.AddService("api.user.UserService", "UserService", &UserServiceService{handle: handle})`,
+		},
+		wantNot: []string{`"user"`},
+	}, {
+		desc:  "service key with legacy alias",
+		key:   KeyService,
+		alias: true,
+		wantIn: []string{
+			`kite.Invoke(destination, "api.user.UserService", "UserService", "GetUser", reqPBData, opts...)`,
+			`pb.ServiceDispatchObject.AddService("api.user.UserService", "UserService", s)`,
+			`pb.ServiceDispatchObject.AddService("user", "UserService", s)`,
+		},
+	}} {
+		opts := DefaultOptions
+		opts.Key = tt.key
+		opts.LegacyKeyAlias = tt.alias
+		got := generateTestFile(t, testServiceFile(), opts)
+		for _, want := range tt.wantIn {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: generated code does not contain %s", tt.desc, want)
+			}
+		}
+		for _, want := range tt.wantNot {
+			if strings.Contains(got, want) {
+				t.Errorf("%s: generated code unexpectedly contains %s", tt.desc, want)
+			}
+		}
+	}
+
+	opts := DefaultOptions
+	opts.LegacyKeyAlias = true
+	gen, f := newTestPlugin(t, testServiceFile())
+	if err := GenerateFileContent(gen, f, gen.NewGeneratedFile("x.pb.go", f.GoImportPath), opts); err == nil {
+		t.Errorf("GenerateFileContent with a legacy key alias for file keys succeeded, want error")
+	}
+}
+
+func TestStreamingMethods(t *testing.T) {
+	for _, tt := range []struct {
+		client, server bool
+		want           string
+	}{
+		{true, false, "method api.user.UserService.GetUser is client streaming"},
+		{false, true, "method api.user.UserService.GetUser is server streaming"},
+		{true, true, "method api.user.UserService.GetUser is bidirectional streaming"},
+	} {
+		fd := testServiceFile()
+		m := fd.Service[0].Method[0]
+		m.ClientStreaming = proto.Bool(tt.client)
+		m.ServerStreaming = proto.Bool(tt.server)
+		gen, f := newTestPlugin(t, fd)
+		err := GenerateFileContent(gen, f, gen.NewGeneratedFile("x.pb.go", f.GoImportPath), DefaultOptions)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("GenerateFileContent(client=%v, server=%v) = %v, want error containing %q", tt.client, tt.server, err, tt.want)
+		}
+	}
+}
+
+func TestServiceErrors(t *testing.T) {
+	for _, ctx := range []bool{false, true} {
+		opts := DefaultOptions
+		opts.Context = ctx
+		got := generateTestFile(t, testServiceFile(), opts)
+		for _, want := range []string{
+			"type UserServiceError struct {",
+			"func (e *UserServiceError) Is(target error) bool {",
+			"ErrUserServiceBadRequest   error = &UserServiceError{Code: UserServiceBadRequest}",
+			"err = &UserServiceError{Code: UserServiceNotFound, Function: function}",
+			"return nil, &UserServiceError{Code: UserServiceBadRequest, Function: function, Err: err}",
+			"return nil, &UserServiceError{Code: UserServiceHandlerError, Function: function, Err: err}",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("generated code does not contain %s", want)
+			}
+		}
+		if strings.Contains(got, `errors.New("function is not found")`) {
+			t.Errorf("generated code still returns an untyped error for unknown functions")
+		}
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	fd := testServiceFile()
+	fd.Service[0].Method = append(fd.Service[0].Method, &descriptorpb.MethodDescriptorProto{
+		Name:       proto.String("list_users"),
+		InputType:  proto.String(".api.user.GetUserRequest"),
+		OutputType: proto.String(".api.user.GetUserResponse"),
+		Options: &descriptorpb.MethodOptions{
+			IdempotencyLevel: descriptorpb.MethodOptions_NO_SIDE_EFFECTS.Enum(),
+		},
+	})
+
+	got := generateTestFile(t, fd, DefaultOptions)
+	if strings.Contains(got, "kitedesc") {
+		t.Errorf("generated code references kitedesc without the metadata option")
+	}
+
+	opts := DefaultOptions
+	opts.Metadata = true
+	got = generateTestFile(t, fd, opts)
+	for _, want := range []string{
+		"var UserService_KiteDesc *kitedesc.ServiceDesc",
+		`UserService_KiteDesc = kitedesc.NewServiceDesc("user", "UserService",`,
+		`kitedesc.LoadService("api.user.UserService", file_api_user_proto_kiteRawDesc),`,
+		"var file_api_user_proto_kiteRawDesc = []byte{",
+		`"GetUser",` + "\n\t\t\"ListUsers\",",
+		"kitedesc.Register(UserService_KiteDesc)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+}
+
+func TestQualifiedMessageTypes(t *testing.T) {
+	fd := testServiceFile()
+	fd.Dependency = []string{"google/protobuf/empty.proto"}
+	fd.Service[0].Method[0].OutputType = proto.String(".google.protobuf.Empty")
+	got := generateTestFile(t, fd, DefaultOptions, &descriptorpb.FileDescriptorProto{
+		Name:        proto.String("google/protobuf/empty.proto"),
+		Package:     proto.String("google.protobuf"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("google.golang.org/protobuf/types/known/emptypb")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Empty")}},
+	})
+	for _, want := range []string{
+		"(response *emptypb.Empty, err error)",
+		"response = new(emptypb.Empty)",
+		"GetUser(*GetUserRequest) (*emptypb.Empty, error)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+}
+
+// TestGolden regenerates testdata/userpb from goldenServiceFile and compares
+// it with the checked-in output, which TestClientErrors runs.
+func TestGolden(t *testing.T) {
+	fd := goldenServiceFile()
+	gen, err := protogen.Options{
+		ImportRewriteFunc: func(importPath protogen.GoImportPath) protogen.GoImportPath {
+			const runtime = "google.golang.org/protobuf/"
+			if strings.HasPrefix(string(importPath), runtime) {
+				return "github.com/golang/protobuf/protobuf/" + importPath[len(runtime):]
+			}
+			return importPath
+		},
+	}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fd.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := gen.FilesByPath[fd.GetName()]
+	g := gengo.GenerateFile(gen, f)
+	if err := GenerateFileContent(gen, f, g, Options{
+		KitePackage: testdataPackage + "/kite",
+		PBPackage:   testdataPackage + "/kite/pb",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := g.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "userpb", "user.pb.go")
+	if *regenerate {
+		if err := os.WriteFile(golden, got, 0666); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%v does not match the generated code; run go test -regenerate", golden)
+	}
+}
+
+type userServer struct{}
+
+func (userServer) GetUser(req *userpb.GetUserRequest) (*userpb.GetUserResponse, error) {
+	if req.GetId() == "" {
+		return nil, errors.New("missing id")
+	}
+	return &userpb.GetUserResponse{}, nil
+}
+
+// rewriteService forwards the calls of a service after rewriting them,
+// to provoke the errors of the server stubs.
+type rewriteService struct {
+	pb.Service
+	function string
+	req      []byte
+}
+
+func (s rewriteService) Do(function string, reqPBData []byte) ([]byte, error) {
+	if s.function != "" {
+		function = s.function
+	}
+	if s.req != nil {
+		reqPBData = s.req
+	}
+	return s.Service.Do(function, reqPBData)
+}
+
+func TestClientErrors(t *testing.T) {
+	userpb.RegUserServiceServer(userServer{})
+	server := pb.ServiceDispatchObject.Service("user", "UserService")
+	defer pb.ServiceDispatchObject.AddService("user", "UserService", server)
+
+	for _, tt := range []struct {
+		desc    string
+		service rewriteService
+		req     *userpb.GetUserRequest
+		wantErr error
+		wantMsg string
+	}{{
+		desc: "success",
+		req:  &userpb.GetUserRequest{Id: "1"},
+	}, {
+		desc:    "handler error",
+		req:     &userpb.GetUserRequest{},
+		wantErr: userpb.ErrUserServiceHandlerError,
+		wantMsg: "UserService.GetUser: handler error: missing id",
+	}, {
+		desc:    "function not found",
+		service: rewriteService{function: "DeleteUser"},
+		req:     &userpb.GetUserRequest{Id: "1"},
+		wantErr: userpb.ErrUserServiceNotFound,
+		wantMsg: "UserService.DeleteUser: function is not found",
+	}, {
+		desc:    "bad request",
+		service: rewriteService{req: []byte{0xff}},
+		req:     &userpb.GetUserRequest{Id: "1"},
+		wantErr: userpb.ErrUserServiceBadRequest,
+		wantMsg: "UserService.GetUser: bad request: ",
+	}} {
+		tt.service.Service = server
+		pb.ServiceDispatchObject.AddService("user", "UserService", tt.service)
+		_, err := userpb.UserService.GetUser("local", tt.req)
+		if tt.wantErr == nil {
+			if err != nil {
+				t.Errorf("%s: GetUser() = %v, want nil error", tt.desc, err)
+			}
+			continue
+		}
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: GetUser() = %v, want errors.Is(err, %v)", tt.desc, err, tt.wantErr)
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), tt.wantMsg) {
+			t.Errorf("%s: GetUser() = %v, want error starting with %q", tt.desc, err, tt.wantMsg)
+		}
+		for _, other := range []error{userpb.ErrUserServiceNotFound, userpb.ErrUserServiceBadRequest, userpb.ErrUserServiceHandlerError} {
+			if other != tt.wantErr && errors.Is(err, other) {
+				t.Errorf("%s: GetUser() = %v, unexpectedly matches %v", tt.desc, err, other)
+			}
+		}
+	}
+
+	_, err := userpb.UserService.GetUser("local", &userpb.GetUserRequest{Id: "\xff"})
+	var e *userpb.UserServiceError
+	if err == nil || errors.As(err, &e) || errors.Unwrap(err) == nil || !strings.HasPrefix(err.Error(), "request marshal err: ") {
+		t.Errorf("GetUser() with an invalid request = %v, want a wrapped marshal error", err)
+	}
+}
+
+// goldenServiceFile returns the file generated into testdata/userpb.
+func goldenServiceFile() *descriptorpb.FileDescriptorProto {
+	fd := testServiceFile()
+	fd.Options.GoPackage = proto.String(testdataPackage + "/userpb")
+	fd.MessageType[0].Field = []*descriptorpb.FieldDescriptorProto{{
+		Name:     proto.String("id"),
+		JsonName: proto.String("id"),
+		Number:   proto.Int32(1),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}}
+	return fd
+}
+
+// testServiceFile returns a file declaring a service with a single method.
+func testServiceFile() *descriptorpb.FileDescriptorProto {
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("api/user.proto"),
+		Package: proto.String("api.user"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/api/user")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{Name: proto.String("GetUserRequest")},
+			{Name: proto.String("GetUserResponse")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserService"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("GetUser"),
+				InputType:  proto.String(".api.user.GetUserRequest"),
+				OutputType: proto.String(".api.user.GetUserResponse"),
+			}},
+		}},
+	}
+}
+
+// newTestPlugin returns a plugin generating fd, which may depend on deps.
+func newTestPlugin(t *testing.T, fd *descriptorpb.FileDescriptorProto, deps ...*descriptorpb.FileDescriptorProto) (*protogen.Plugin, *protogen.File) {
+	t.Helper()
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fd.GetName()},
+		ProtoFile:      append(deps, fd),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gen, gen.FilesByPath[fd.GetName()]
+}
+
+// generateTestFile generates the .pb.go file for fd including the kite
+// services and returns the generated source.
+func generateTestFile(t *testing.T, fd *descriptorpb.FileDescriptorProto, opts Options, deps ...*descriptorpb.FileDescriptorProto) string {
+	t.Helper()
+	gen, f := newTestPlugin(t, fd, deps...)
+	g := gengo.GenerateFile(gen, f)
+	if err := GenerateFileContent(gen, f, g, opts); err != nil {
+		t.Fatal(err)
+	}
+	b, err := g.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
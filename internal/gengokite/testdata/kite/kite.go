@@ -0,0 +1,34 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kite is a stand-in for the kite rpc runtime used to test the
+// generated code. It calls services registered with pb in-process and,
+// like the runtime, reports the error of a remote call by its message only.
+package kite
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/internal/gengokite/testdata/kite/pb"
+)
+
+// Destination addresses the process serving a call.
+type Destination string
+
+// Option configures a call.
+type Option func()
+
+// Invoke calls function of the service registered as protoName and service.
+func Invoke(destination Destination, protoName, service, function string, data []byte, opts ...Option) ([]byte, error) {
+	s := pb.ServiceDispatchObject.Service(protoName, service)
+	if s == nil {
+		return nil, fmt.Errorf("kite: %v: service %v.%v is not registered", destination, protoName, service)
+	}
+	res, err := s.Do(function, data)
+	if err != nil {
+		return nil, errors.New("kite: remote error: " + err.Error())
+	}
+	return res, nil
+}
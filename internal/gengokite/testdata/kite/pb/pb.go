@@ -0,0 +1,43 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pb is a stand-in for the kite service dispatch package used to
+// test the generated code.
+package pb
+
+import "github.com/golang/protobuf/protobuf/proto"
+
+// Service is a registered service, dispatching calls by function name.
+type Service interface {
+	Do(function string, reqPBData []byte) (resPBData []byte, err error)
+}
+
+// Dispatcher holds the registered services.
+type Dispatcher struct {
+	services map[[2]string]Service
+}
+
+// ServiceDispatchObject is the dispatcher that generated code registers with.
+var ServiceDispatchObject = &Dispatcher{services: make(map[[2]string]Service)}
+
+// AddService registers s as protoName and serviceName.
+func (d *Dispatcher) AddService(protoName, serviceName string, s Service) {
+	d.services[[2]string{protoName, serviceName}] = s
+}
+
+// Service returns the service registered as protoName and serviceName,
+// or nil if there is none.
+func (d *Dispatcher) Service(protoName, serviceName string) Service {
+	return d.services[[2]string{protoName, serviceName}]
+}
+
+// Marshal returns the wire-format encoding of m.
+func Marshal(m proto.Message) ([]byte, error) {
+	return proto.Marshal(m)
+}
+
+// Unmarshal parses the wire-format message in b and places the result in m.
+func Unmarshal(b []byte, m proto.Message) error {
+	return proto.Unmarshal(b, m)
+}
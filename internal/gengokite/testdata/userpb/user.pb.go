@@ -0,0 +1,360 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        (unknown)
+// source: api/user.proto
+
+package userpb
+
+import (
+	errors "errors"
+	fmt "fmt"
+	kite "github.com/golang/protobuf/internal/gengokite/testdata/kite"
+	pb "github.com/golang/protobuf/internal/gengokite/testdata/kite/pb"
+	protoreflect "github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	protoimpl "github.com/golang/protobuf/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	strings "strings"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty" bson:"_id"`
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_user_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_user_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_user_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_api_user_proto_rawDescGZIP(), []int{1}
+}
+
+var File_api_user_proto protoreflect.FileDescriptor
+
+var file_api_user_proto_rawDesc = []byte{
+	0x0a, 0x0e, 0x61, 0x70, 0x69, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x08, 0x61, 0x70, 0x69, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x22, 0x20, 0x0a, 0x0e, 0x47, 0x65,
+	0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x11, 0x0a, 0x0f,
+	0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32,
+	0x4d, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3e,
+	0x0a, 0x07, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x75, 0x73, 0x65, 0x72, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x75, 0x73, 0x65, 0x72, 0x2e, 0x47,
+	0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x3f,
+	0x5a, 0x3d, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c,
+	0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x69, 0x6e, 0x74,
+	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x65, 0x6e, 0x67, 0x6f, 0x6b, 0x69, 0x74, 0x65, 0x2f,
+	0x74, 0x65, 0x73, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2f, 0x75, 0x73, 0x65, 0x72, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_user_proto_rawDescOnce sync.Once
+	file_api_user_proto_rawDescData = file_api_user_proto_rawDesc
+)
+
+func file_api_user_proto_rawDescGZIP() []byte {
+	file_api_user_proto_rawDescOnce.Do(func() {
+		file_api_user_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_user_proto_rawDescData)
+	})
+	return file_api_user_proto_rawDescData
+}
+
+var file_api_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_user_proto_goTypes = []interface{}{
+	(*GetUserRequest)(nil),  // 0: api.user.GetUserRequest
+	(*GetUserResponse)(nil), // 1: api.user.GetUserResponse
+}
+var file_api_user_proto_depIdxs = []int32{
+	0, // 0: api.user.UserService.GetUser:input_type -> api.user.GetUserRequest
+	1, // 1: api.user.UserService.GetUser:output_type -> api.user.GetUserResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_api_user_proto_init() }
+func file_api_user_proto_init() {
+	if File_api_user_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_api_user_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_user_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetUserResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_user_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_user_proto_goTypes,
+		DependencyIndexes: file_api_user_proto_depIdxs,
+		MessageInfos:      file_api_user_proto_msgTypes,
+	}.Build()
+	File_api_user_proto = out.File
+	file_api_user_proto_rawDesc = nil
+	file_api_user_proto_goTypes = nil
+	file_api_user_proto_depIdxs = nil
+}
+
+// UserService rpc客户端实例
+var UserService = &userService{}
+
+type userService struct{}
+
+// GetUser 通过destination调用consul rpc服务
+func (c *userService) GetUser(destination kite.Destination, request *GetUserRequest, opts ...kite.Option) (response *GetUserResponse, err error) {
+	reqPBData, err := pb.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("request marshal err: %w", err)
+	}
+	resPBData, err := kite.Invoke(destination, "user", "UserService", "GetUser", reqPBData, opts...)
+	if err != nil {
+		return nil, parseUserServiceError(err)
+	}
+	response = new(GetUserResponse)
+	err = pb.Unmarshal(resPBData, response)
+	return
+}
+
+// UserServiceServer is the server API for UserService service.
+type UserServiceServer interface {
+	GetUser(*GetUserRequest) (*GetUserResponse, error)
+}
+
+type UserServiceService struct {
+	handle UserServiceServer
+}
+
+// RegUserServiceServer 注册UserService服务
+func RegUserServiceServer(handle UserServiceServer) {
+	pb.ServiceDispatchObject.AddService("user", "UserService", &UserServiceService{handle: handle})
+}
+
+// UserServiceErrorCode classifies the errors returned by the UserService server stubs.
+type UserServiceErrorCode int32
+
+const (
+	// UserServiceNotFound reports a call of a function the service does not provide.
+	UserServiceNotFound UserServiceErrorCode = 1
+	// UserServiceBadRequest reports a request that could not be unmarshaled.
+	UserServiceBadRequest UserServiceErrorCode = 2
+	// UserServiceHandlerError reports an error returned by the UserServiceServer handler.
+	UserServiceHandlerError UserServiceErrorCode = 3
+)
+
+func (c UserServiceErrorCode) String() string {
+	switch c {
+	case UserServiceNotFound:
+		return "function is not found"
+	case UserServiceBadRequest:
+		return "bad request"
+	case UserServiceHandlerError:
+		return "handler error"
+	}
+	return "unknown error"
+}
+
+// UserServiceError is an error returned by the UserService server stubs
+// and reported to the UserService client.
+// Use errors.Is with the ErrUserService sentinel errors to match the error code.
+type UserServiceError struct {
+	Code     UserServiceErrorCode
+	Function string
+	Err      error
+}
+
+// Error returns the error message, which is parsed back into a UserServiceError
+// by the UserService client.
+func (e *UserServiceError) Error() string {
+	s := e.Code.String()
+	if e.Function != "" {
+		s = "UserService." + e.Function + ": " + s
+	}
+	if e.Err != nil {
+		s += ": " + e.Err.Error()
+	}
+	return s
+}
+
+func (e *UserServiceError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a UserServiceError with the same code.
+func (e *UserServiceError) Is(target error) bool {
+	t, ok := target.(*UserServiceError)
+	return ok && t.Code == e.Code
+}
+
+// Sentinel errors for each UserServiceErrorCode.
+var (
+	ErrUserServiceNotFound     error = &UserServiceError{Code: UserServiceNotFound}
+	ErrUserServiceBadRequest   error = &UserServiceError{Code: UserServiceBadRequest}
+	ErrUserServiceHandlerError error = &UserServiceError{Code: UserServiceHandlerError}
+)
+
+// parseUserServiceError maps an error returned by the kite runtime back to
+// the UserServiceError reported by the server stub.
+// Other errors, such as those of the transport, are returned unchanged.
+func parseUserServiceError(err error) error {
+	var e *UserServiceError
+	if errors.As(err, &e) {
+		return err
+	}
+	msg := err.Error()
+	i := strings.Index(msg, "UserService.")
+	if i < 0 {
+		return err
+	}
+	msg = msg[i+len("UserService."):]
+	i = strings.Index(msg, ": ")
+	if i < 0 {
+		return err
+	}
+	function := msg[:i]
+	msg = msg[i+len(": "):]
+	for _, code := range []UserServiceErrorCode{UserServiceNotFound, UserServiceBadRequest, UserServiceHandlerError} {
+		s := code.String()
+		switch {
+		case msg == s:
+			return &UserServiceError{Code: code, Function: function}
+		case strings.HasPrefix(msg, s+": "):
+			return &UserServiceError{Code: code, Function: function, Err: errors.New(msg[len(s)+2:])}
+		}
+	}
+	return err
+}
+
+func (s *UserServiceService) Do(function string, reqPBData []byte) (resPBData []byte, err error) {
+	switch function {
+	case "GetUser":
+		return s.GetUser(function, reqPBData)
+	default:
+		err = &UserServiceError{Code: UserServiceNotFound, Function: function}
+	}
+	return
+}
+
+func (s *UserServiceService) GetUser(function string, reqPBData []byte) (resPBData []byte, err error) {
+	req := new(GetUserRequest)
+	if err = pb.Unmarshal(reqPBData, req); err != nil {
+		return nil, &UserServiceError{Code: UserServiceBadRequest, Function: function, Err: err}
+	}
+	res, err := s.handle.GetUser(req)
+	if err != nil {
+		return nil, &UserServiceError{Code: UserServiceHandlerError, Function: function, Err: err}
+	}
+	return pb.Marshal(res)
+}
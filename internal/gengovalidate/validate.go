@@ -0,0 +1,351 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gengovalidate contains the validation code generator.
+package gengovalidate
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	gengo "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	"github.com/golang/protobuf/protoc-gen-go/govalidate"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+)
+
+const (
+	regexpPackage        = protogen.GoImportPath("regexp")
+	utf8Package          = protogen.GoImportPath("unicode/utf8")
+	protovalidatePackage = protogen.GoImportPath("github.com/golang/protobuf/protovalidate")
+)
+
+// GenerateFileContent generates a Validate method for every message in file,
+// excluding the package statement.
+//
+// The Validate method checks the (govalidate.rules) options of the fields of
+// the message and validates its nested messages.
+func GenerateFileContent(gen *protogen.Plugin, file *protogen.File, g *protogen.GeneratedFile) error {
+	var messages []*protogen.Message
+	var walk func([]*protogen.Message)
+	walk = func(ms []*protogen.Message) {
+		for _, m := range ms {
+			if m.Desc.IsMapEntry() {
+				continue
+			}
+			messages = append(messages, m)
+			walk(m.Messages)
+		}
+	}
+	walk(file.Messages)
+
+	rules := make(map[*protogen.Field]*govalidate.FieldRules)
+	for _, m := range messages {
+		for _, field := range m.Fields {
+			r := fieldRules(field)
+			if r == nil {
+				continue
+			}
+			if err := checkRules(field, r); err != nil {
+				return fmt.Errorf("protoc-gen-go: %v: %v: invalid (govalidate.rules) option: %v",
+					file.Desc.Path(), field.Desc.FullName(), err)
+			}
+			rules[field] = r
+		}
+	}
+	for _, m := range messages {
+		genMessage(g, m, rules)
+	}
+	return nil
+}
+
+// fieldRules returns the (govalidate.rules) option of field, or nil if unset.
+func fieldRules(field *protogen.Field) *govalidate.FieldRules {
+	opts := field.Desc.Options().(*descriptorpb.FieldOptions)
+	if !proto.HasExtension(opts, govalidate.E_Rules) {
+		return nil
+	}
+	return proto.GetExtension(opts, govalidate.E_Rules).(*govalidate.FieldRules)
+}
+
+// checkRules reports an error for rules that do not apply to field.
+func checkRules(field *protogen.Field, r *govalidate.FieldRules) error {
+	fd := field.Desc
+	kind := fd.Kind()
+	if fd.IsMap() {
+		if r.GetRequired() || hasValueRules(r) {
+			return fmt.Errorf("only min_items and max_items apply to map fields")
+		}
+	}
+	if fd.IsList() && r.GetRequired() {
+		return fmt.Errorf("required does not apply to repeated fields, use min_items instead")
+	}
+	if !fd.IsList() && !fd.IsMap() && (r.MinItems != nil || r.MaxItems != nil) {
+		return fmt.Errorf("min_items and max_items apply only to repeated and map fields")
+	}
+	if (r.MinLen != nil || r.MaxLen != nil) && kind != protoreflect.StringKind && kind != protoreflect.BytesKind {
+		return fmt.Errorf("min_len and max_len apply only to string and bytes fields")
+	}
+	if r.Pattern != nil {
+		if kind != protoreflect.StringKind {
+			return fmt.Errorf("pattern applies only to string fields")
+		}
+		if _, err := regexp.Compile(r.GetPattern()); err != nil {
+			return err
+		}
+	}
+	if r.Min != nil || r.Max != nil {
+		if !isNumeric(kind) {
+			return fmt.Errorf("min and max apply only to numeric fields")
+		}
+		for _, v := range []*float64{r.Min, r.Max} {
+			if v == nil {
+				continue
+			}
+			if _, err := numberLiteral(kind, *v); err != nil {
+				return err
+			}
+		}
+	}
+	if r.DefinedOnly != nil && kind != protoreflect.EnumKind {
+		return fmt.Errorf("defined_only applies only to enum fields")
+	}
+	if r.MinLen != nil && r.MaxLen != nil && r.GetMinLen() > r.GetMaxLen() {
+		return fmt.Errorf("min_len %d is greater than max_len %d", r.GetMinLen(), r.GetMaxLen())
+	}
+	if r.Min != nil && r.Max != nil && r.GetMin() > r.GetMax() {
+		return fmt.Errorf("min %v is greater than max %v", r.GetMin(), r.GetMax())
+	}
+	if r.MinItems != nil && r.MaxItems != nil && r.GetMinItems() > r.GetMaxItems() {
+		return fmt.Errorf("min_items %d is greater than max_items %d", r.GetMinItems(), r.GetMaxItems())
+	}
+	return nil
+}
+
+// hasValueRules reports whether r constrains the value of a field,
+// or of each element of a repeated field.
+func hasValueRules(r *govalidate.FieldRules) bool {
+	return r.MinLen != nil || r.MaxLen != nil || r.Min != nil || r.Max != nil ||
+		r.Pattern != nil || r.GetDefinedOnly()
+}
+
+func isNumeric(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind,
+		protoreflect.FloatKind, protoreflect.DoubleKind:
+		return true
+	}
+	return false
+}
+
+// numberLiteral returns v as a Go constant which is representable by the Go
+// type of a field of the given kind.
+func numberLiteral(kind protoreflect.Kind, v float64) (string, error) {
+	switch kind {
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if math.IsNaN(v) || math.IsInf(v, 0) || (kind == protoreflect.FloatKind && math.Abs(v) > math.MaxFloat32) {
+			return "", fmt.Errorf("bound %v is out of range of %v fields", v, kind)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	}
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	var err error
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		_, err = strconv.ParseInt(s, 10, 32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		_, err = strconv.ParseInt(s, 10, 64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		_, err = strconv.ParseUint(s, 10, 32)
+	default:
+		_, err = strconv.ParseUint(s, 10, 64)
+	}
+	if err != nil {
+		return "", fmt.Errorf("bound %v is not an integer in range of %v fields", v, kind)
+	}
+	return s, nil
+}
+
+func genMessage(g *protogen.GeneratedFile, m *protogen.Message, rules map[*protogen.Field]*govalidate.FieldRules) {
+	for _, field := range m.Fields {
+		if r := rules[field]; r != nil && r.Pattern != nil {
+			g.P("var ", patternVar(field), " = ", regexpPackage.Ident("MustCompile"), "(", strconv.Quote(r.GetPattern()), ")")
+			g.P()
+		}
+	}
+
+	g.P("// Validate checks the constraints on the fields of ", m.GoIdent.GoName, " and validates its")
+	g.P("// nested messages. It returns a protovalidate.Error listing every violation, or nil.")
+	g.P("func (x *", m.GoIdent, ") Validate() error {")
+	g.P("if x == nil {")
+	g.P("return nil")
+	g.P("}")
+	g.P("var errs ", protovalidatePackage.Ident("Error"))
+	for _, field := range m.Fields {
+		genField(g, field, rules[field])
+	}
+	g.P("return errs.Err()")
+	g.P("}")
+	g.P()
+}
+
+func patternVar(field *protogen.Field) string {
+	return "validatePattern_" + field.Parent.GoIdent.GoName + "_" + field.GoName
+}
+
+func genField(g *protogen.GeneratedFile, field *protogen.Field, r *govalidate.FieldRules) {
+	fd := field.Desc
+	path := strconv.Quote(string(fd.Name()))
+	getter := "x.Get" + field.GoName + "()"
+	isMessage := fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind
+
+	switch {
+	case fd.IsMap():
+		genItemRules(g, getter, path, r)
+		if fd.MapValue().Kind() == protoreflect.MessageKind {
+			g.P("for k, v := range ", getter, " {")
+			g.P("errs.AddNested(", protovalidatePackage.Ident("KeyPath"), "(", path, ", k), v)")
+			g.P("}")
+		}
+	case fd.IsList():
+		genItemRules(g, getter, path, r)
+		if !isMessage && (r == nil || !hasValueRules(r)) {
+			return
+		}
+		g.P("for i, v := range ", getter, " {")
+		g.P("p := ", protovalidatePackage.Ident("IndexPath"), "(", path, ", i)")
+		if isMessage {
+			g.P("errs.AddNested(p, v)")
+		} else {
+			genValueRules(g, field, r, "v", "p")
+		}
+		g.P("}")
+	case isMessage:
+		if r.GetRequired() {
+			g.P("if ", getter, " == nil {")
+			genViolation(g, path, "required", "is required")
+			g.P("}")
+		}
+		g.P("errs.AddNested(", path, ", ", getter, ")")
+	case fd.HasPresence():
+		// The value rules of a field with presence only apply if it is set.
+		var init, set, unset string
+		if gengo.MessageAPILevel(field.Parent) != gengo.APIOpen {
+			// Opaque messages have no exported fields, so use the Has method
+			// that is generated for all API levels other than open.
+			set, unset = "x.Has"+field.GoName+"()", "!x.Has"+field.GoName+"()"
+		} else if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+			init = "_, ok := x.Get" + field.Oneof.GoName + "().(*" + g.QualifiedGoIdent(field.GoIdent) + "); "
+			set, unset = "ok", "!ok"
+		} else {
+			set, unset = "x."+field.GoName+" != nil", "x."+field.GoName+" == nil"
+		}
+		switch {
+		case r != nil && hasValueRules(r):
+			g.P("if ", init, set, " {")
+			genValueRules(g, field, r, getter, path)
+			if r.GetRequired() {
+				g.P("} else {")
+				genViolation(g, path, "required", "is required")
+			}
+			g.P("}")
+		case r.GetRequired():
+			g.P("if ", init, unset, " {")
+			genViolation(g, path, "required", "is required")
+			g.P("}")
+		}
+	default:
+		if r.GetRequired() {
+			var zero string
+			switch fd.Kind() {
+			case protoreflect.StringKind:
+				zero = getter + ` == ""`
+			case protoreflect.BytesKind:
+				zero = "len(" + getter + ") == 0"
+			case protoreflect.BoolKind:
+				zero = "!" + getter
+			default:
+				zero = getter + " == 0"
+			}
+			g.P("if ", zero, " {")
+			genViolation(g, path, "required", "is required")
+			g.P("}")
+		}
+		if r != nil && hasValueRules(r) {
+			genValueRules(g, field, r, getter, path)
+		}
+	}
+}
+
+// genItemRules generates the checks of the number of elements of a repeated
+// or map field.
+func genItemRules(g *protogen.GeneratedFile, getter, path string, r *govalidate.FieldRules) {
+	if r == nil {
+		return
+	}
+	if r.MinItems != nil {
+		g.P("if len(", getter, ") < ", r.GetMinItems(), " {")
+		genViolation(g, path, "min_items", fmt.Sprintf("must have at least %d elements", r.GetMinItems()))
+		g.P("}")
+	}
+	if r.MaxItems != nil {
+		g.P("if len(", getter, ") > ", r.GetMaxItems(), " {")
+		genViolation(g, path, "max_items", fmt.Sprintf("must have at most %d elements", r.GetMaxItems()))
+		g.P("}")
+	}
+}
+
+// genValueRules generates the checks of the value v of a field,
+// or of an element of a repeated field, whose path is the Go expression path.
+func genValueRules(g *protogen.GeneratedFile, field *protogen.Field, r *govalidate.FieldRules, v, path string) {
+	kind := field.Desc.Kind()
+	length, unit := "len("+v+")", "bytes"
+	if kind == protoreflect.StringKind {
+		length, unit = g.QualifiedGoIdent(utf8Package.Ident("RuneCountInString"))+"("+v+")", "characters"
+	}
+	if r.MinLen != nil {
+		g.P("if ", length, " < ", r.GetMinLen(), " {")
+		genViolation(g, path, "min_len", fmt.Sprintf("must be at least %d %s long", r.GetMinLen(), unit))
+		g.P("}")
+	}
+	if r.MaxLen != nil {
+		g.P("if ", length, " > ", r.GetMaxLen(), " {")
+		genViolation(g, path, "max_len", fmt.Sprintf("must be at most %d %s long", r.GetMaxLen(), unit))
+		g.P("}")
+	}
+	if r.Min != nil {
+		lit, _ := numberLiteral(kind, r.GetMin())
+		g.P("if ", v, " < ", lit, " {")
+		genViolation(g, path, "min", "must be at least "+lit)
+		g.P("}")
+	}
+	if r.Max != nil {
+		lit, _ := numberLiteral(kind, r.GetMax())
+		g.P("if ", v, " > ", lit, " {")
+		genViolation(g, path, "max", "must be at most "+lit)
+		g.P("}")
+	}
+	if r.Pattern != nil {
+		g.P("if !", patternVar(field), ".MatchString(", v, ") {")
+		genViolation(g, path, "pattern", "must match "+strconv.Quote(r.GetPattern()))
+		g.P("}")
+	}
+	if r.GetDefinedOnly() {
+		g.P("if ", v, ".Descriptor().Values().ByNumber(", v, ".Number()) == nil {")
+		genViolation(g, path, "defined_only", "must be a defined enum value")
+		g.P("}")
+	}
+}
+
+func genViolation(g *protogen.GeneratedFile, path, rule, message string) {
+	g.P("errs.Add(", path, ", ", strconv.Quote(rule), ", ", strconv.Quote(message), ")")
+}
@@ -0,0 +1,215 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gengovalidate
+
+import (
+	"strings"
+	"testing"
+
+	gengo "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/govalidate"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+	"github.com/golang/protobuf/protobuf/types/pluginpb"
+)
+
+func TestValidate(t *testing.T) {
+	got, err := generateTestFile(t, testFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"func (x *User) Validate() error {",
+		"func (x *Address) Validate() error {",
+		"var errs protovalidate.Error",
+		"return errs.Err()",
+		// Strings and bytes.
+		"if utf8.RuneCountInString(x.GetName()) < 1 {\n\t\terrs.Add(\"name\", \"min_len\", \"must be at least 1 characters long\")",
+		"if utf8.RuneCountInString(x.GetName()) > 8 {",
+		"var validatePattern_User_Email = regexp.MustCompile(\"^[^@]+@[^@]+$\")",
+		"if !validatePattern_User_Email.MatchString(x.GetEmail()) {",
+		"if len(x.GetData()) == 0 {\n\t\terrs.Add(\"data\", \"required\", \"is required\")",
+		"if len(x.GetData()) > 4 {",
+		// Numbers and enums.
+		"if x.GetAge() < 0 {",
+		"if x.GetAge() > 150 {\n\t\terrs.Add(\"age\", \"max\", \"must be at most 150\")",
+		"if x.GetStatus().Descriptor().Values().ByNumber(x.GetStatus().Number()) == nil {",
+		// Messages.
+		"if x.GetAddress() == nil {\n\t\terrs.Add(\"address\", \"required\", \"is required\")",
+		"errs.AddNested(\"address\", x.GetAddress())",
+		"errs.AddNested(\"home\", x.GetHome())",
+		// Repeated and map fields.
+		"if len(x.GetTags()) < 1 {",
+		"if len(x.GetTags()) > 3 {",
+		"p := protovalidate.IndexPath(\"tags\", i)\n\t\tif utf8.RuneCountInString(v) > 4 {\n\t\t\terrs.Add(p, \"max_len\",",
+		"p := protovalidate.IndexPath(\"others\", i)\n\t\terrs.AddNested(p, v)",
+		"if len(x.GetByName()) > 2 {",
+		"errs.AddNested(protovalidate.KeyPath(\"by_name\", k), v)",
+		// Fields with presence.
+		"if x.Nickname != nil {\n\t\tif utf8.RuneCountInString(x.GetNickname()) < 2 {",
+		"} else {\n\t\terrs.Add(\"nickname\", \"required\", \"is required\")",
+		"if _, ok := x.GetContact().(*User_Phone); ok {",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+}
+
+func TestValidateOpaque(t *testing.T) {
+	defer func(level gengo.APILevel) { gengo.DefaultAPILevel = level }(gengo.DefaultAPILevel)
+	gengo.DefaultAPILevel = gengo.APIOpaque
+
+	got, err := generateTestFile(t, testFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"if x.HasNickname() {\n\t\tif utf8.RuneCountInString(x.GetNickname()) < 2 {",
+		"if x.HasPhone() {",
+		"errs.AddNested(\"home\", x.GetHome())",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	for _, tt := range []struct {
+		field int
+		rules *govalidate.FieldRules
+		want  string
+	}{
+		{2, &govalidate.FieldRules{MinLen: proto.Uint64(1)}, "min_len and max_len apply only to string and bytes fields"},
+		{0, &govalidate.FieldRules{Min: proto.Float64(1)}, "min and max apply only to numeric fields"},
+		{2, &govalidate.FieldRules{Min: proto.Float64(1.5)}, "bound 1.5 is not an integer"},
+		{2, &govalidate.FieldRules{Max: proto.Float64(1 << 40)}, "is not an integer in range of int32 fields"},
+		{2, &govalidate.FieldRules{Min: proto.Float64(10), Max: proto.Float64(1)}, "min 10 is greater than max 1"},
+		{1, &govalidate.FieldRules{Pattern: proto.String("(")}, "missing closing )"},
+		{0, &govalidate.FieldRules{DefinedOnly: proto.Bool(true)}, "defined_only applies only to enum fields"},
+		{0, &govalidate.FieldRules{MinItems: proto.Uint64(1)}, "apply only to repeated and map fields"},
+		{5, &govalidate.FieldRules{Required: proto.Bool(true)}, "required does not apply to repeated fields"},
+		{7, &govalidate.FieldRules{MinLen: proto.Uint64(1)}, "only min_items and max_items apply to map fields"},
+	} {
+		fd := testFile()
+		field := fd.MessageType[0].Field[tt.field]
+		field.Options = &descriptorpb.FieldOptions{}
+		proto.SetExtension(field.Options, govalidate.E_Rules, tt.rules)
+		_, err := generateTestFile(t, fd)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("rules %v on %v: error = %v, want error containing %q", tt.rules, field.GetName(), err, tt.want)
+		}
+	}
+}
+
+// testFile returns a file declaring a User message with rules on most kinds of fields.
+func testFile() *descriptorpb.FileDescriptorProto {
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	repeated := descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	field := func(name string, num int32, label *descriptorpb.FieldDescriptorProto_Label, typ descriptorpb.FieldDescriptorProto_Type, typeName string, rules *govalidate.FieldRules) *descriptorpb.FieldDescriptorProto {
+		fd := &descriptorpb.FieldDescriptorProto{
+			Name:   proto.String(name),
+			Number: proto.Int32(num),
+			Label:  label,
+			Type:   typ.Enum(),
+		}
+		if typeName != "" {
+			fd.TypeName = proto.String(typeName)
+		}
+		if rules != nil {
+			fd.Options = &descriptorpb.FieldOptions{}
+			proto.SetExtension(fd.Options, govalidate.E_Rules, rules)
+		}
+		return fd
+	}
+	const (
+		stringType  = descriptorpb.FieldDescriptorProto_TYPE_STRING
+		bytesType   = descriptorpb.FieldDescriptorProto_TYPE_BYTES
+		int32Type   = descriptorpb.FieldDescriptorProto_TYPE_INT32
+		enumType    = descriptorpb.FieldDescriptorProto_TYPE_ENUM
+		messageType = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE
+	)
+	nickname := field("nickname", 9, optional, stringType, "", &govalidate.FieldRules{Required: proto.Bool(true), MinLen: proto.Uint64(2)})
+	nickname.Proto3Optional = proto.Bool(true)
+	nickname.OneofIndex = proto.Int32(1)
+	phone := field("phone", 10, optional, stringType, "", &govalidate.FieldRules{MinLen: proto.Uint64(5)})
+	phone.OneofIndex = proto.Int32(0)
+	home := field("home", 11, optional, messageType, ".api.user.Address", nil)
+	home.OneofIndex = proto.Int32(0)
+
+	return &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("api/user.proto"),
+		Package: proto.String("api.user"),
+		Syntax:  proto.String("proto3"),
+		Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/api/user")},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Status"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("UNKNOWN"), Number: proto.Int32(0)},
+				{Name: proto.String("ACTIVE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("User"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("name", 1, optional, stringType, "", &govalidate.FieldRules{MinLen: proto.Uint64(1), MaxLen: proto.Uint64(8)}),
+				field("email", 2, optional, stringType, "", &govalidate.FieldRules{Pattern: proto.String("^[^@]+@[^@]+$")}),
+				field("age", 3, optional, int32Type, "", &govalidate.FieldRules{Min: proto.Float64(0), Max: proto.Float64(150)}),
+				field("status", 4, optional, enumType, ".api.user.Status", &govalidate.FieldRules{DefinedOnly: proto.Bool(true)}),
+				field("address", 5, optional, messageType, ".api.user.Address", &govalidate.FieldRules{Required: proto.Bool(true)}),
+				field("tags", 6, repeated, stringType, "", &govalidate.FieldRules{MinItems: proto.Uint64(1), MaxItems: proto.Uint64(3), MaxLen: proto.Uint64(4)}),
+				field("others", 7, repeated, messageType, ".api.user.Address", nil),
+				field("by_name", 8, repeated, messageType, ".api.user.User.ByNameEntry", &govalidate.FieldRules{MaxItems: proto.Uint64(2)}),
+				nickname,
+				phone,
+				home,
+				field("data", 12, optional, bytesType, "", &govalidate.FieldRules{Required: proto.Bool(true), MaxLen: proto.Uint64(4)}),
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("ByNameEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("key", 1, optional, stringType, "", nil),
+					field("value", 2, optional, messageType, ".api.user.Address", nil),
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+			OneofDecl: []*descriptorpb.OneofDescriptorProto{
+				{Name: proto.String("contact")},
+				{Name: proto.String("_nickname")},
+			},
+		}, {
+			Name: proto.String("Address"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				field("zip", 1, optional, stringType, "", &govalidate.FieldRules{Required: proto.Bool(true)}),
+			},
+		}},
+	}
+}
+
+// generateTestFile generates the .pb.go file for fd including the Validate
+// methods and returns the generated source.
+func generateTestFile(t *testing.T, fd *descriptorpb.FileDescriptorProto) (string, error) {
+	t.Helper()
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fd.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := gen.FilesByPath[fd.GetName()]
+	g := gengo.GenerateFile(gen, f)
+	if err := GenerateFileContent(gen, f, g); err != nil {
+		return "", err
+	}
+	b, err := g.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), nil
+}
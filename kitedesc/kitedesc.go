@@ -0,0 +1,228 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kitedesc describes the kite services generated by protoc-gen-go.
+//
+// With the kite_metadata parameter, protoc-gen-go generates a ServiceDesc
+// variable named <Service>_KiteDesc for every kite service, which lets
+// gateways and other tools introspect the methods of a service in the same
+// way that a grpc.ServiceDesc does for gRPC services.
+//
+// The generated code also registers each ServiceDesc, so that tools can find
+// kite services and methods by their full names, and use protoregistry to
+// obtain the Go types of their request and response messages.
+//
+// The descriptors of the services are those of the protobuf runtime in this
+// module. If the generated code imports another runtime, such as
+// google.golang.org/protobuf, they are built from a copy of the file descriptor
+// embedded in the generated code, and the Go types of messages are only found
+// if they are also registered with the runtime in this module.
+package kitedesc
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/reflect/protodesc"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	"github.com/golang/protobuf/protobuf/reflect/protoregistry"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+)
+
+// ServiceDesc describes a kite service.
+type ServiceDesc struct {
+	// ProtoName is the name that the service is registered under with
+	// the kite service dispatcher.
+	ProtoName string
+	// ServiceName is the name of the service in kite calls,
+	// which is the Go name of the service.
+	ServiceName string
+	// Descriptor is the descriptor of the proto service.
+	Descriptor protoreflect.ServiceDescriptor
+	// Methods describes the methods of the service in declaration order.
+	Methods []MethodDesc
+}
+
+// MethodDesc describes a method of a kite service.
+type MethodDesc struct {
+	// Function is the name of the method in kite calls,
+	// which is the Go name of the method.
+	Function string
+	// FullName is the full name of the method (e.g., "pkg.Service.Method").
+	FullName protoreflect.FullName
+	// Input and Output are the descriptors of the request and response messages.
+	Input, Output protoreflect.MessageDescriptor
+	// IdempotencyLevel is the idempotency_level option of the method.
+	IdempotencyLevel descriptorpb.MethodOptions_IdempotencyLevel
+	// Descriptor is the descriptor of the proto method.
+	Descriptor protoreflect.MethodDescriptor
+	// Service is the service that the method belongs to.
+	Service *ServiceDesc
+}
+
+// Idempotent reports whether calling the method multiple times has the same
+// effect as calling it once, which is the case for methods marked as
+// NO_SIDE_EFFECTS or IDEMPOTENT.
+func (m *MethodDesc) Idempotent() bool {
+	switch m.IdempotencyLevel {
+	case descriptorpb.MethodOptions_NO_SIDE_EFFECTS, descriptorpb.MethodOptions_IDEMPOTENT:
+		return true
+	}
+	return false
+}
+
+// InputType returns the Go type of the request message from
+// protoregistry.GlobalTypes.
+func (m *MethodDesc) InputType() (protoreflect.MessageType, error) {
+	return protoregistry.GlobalTypes.FindMessageByName(m.Input.FullName())
+}
+
+// OutputType returns the Go type of the response message from
+// protoregistry.GlobalTypes.
+func (m *MethodDesc) OutputType() (protoreflect.MessageType, error) {
+	return protoregistry.GlobalTypes.FindMessageByName(m.Output.FullName())
+}
+
+// LoadService returns the descriptor of the service with the given full name,
+// declared in the file whose serialized FileDescriptorProto is rawDesc.
+//
+// If generated code using the runtime in this module has registered the file
+// with protoregistry.GlobalFiles, the registered descriptor is returned.
+// Otherwise, the descriptor is built from rawDesc, resolving the dependencies
+// of the file in protoregistry.GlobalFiles where they are registered.
+// It panics if rawDesc is invalid or does not declare the service.
+//
+// It is called by generated code.
+func LoadService(name protoreflect.FullName, rawDesc []byte) protoreflect.ServiceDescriptor {
+	if d, err := protoregistry.GlobalFiles.FindDescriptorByName(name); err == nil {
+		if sd, ok := d.(protoreflect.ServiceDescriptor); ok {
+			return sd
+		}
+	}
+	fdp := new(descriptorpb.FileDescriptorProto)
+	if err := proto.Unmarshal(rawDesc, fdp); err != nil {
+		panic(fmt.Sprintf("kitedesc: invalid descriptor of service %v: %v", name, err))
+	}
+	fd, err := protodesc.FileOptions{AllowUnresolvable: true}.New(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		panic(fmt.Sprintf("kitedesc: invalid descriptor of service %v: %v", name, err))
+	}
+	sd := fd.Services().ByName(name.Name())
+	if sd == nil || sd.FullName() != name {
+		panic(fmt.Sprintf("kitedesc: file %v does not declare service %v", fd.Path(), name))
+	}
+	return sd
+}
+
+// NewServiceDesc returns the description of the kite service sd.
+// The functions are the kite function names of the methods of sd,
+// in declaration order. It panics if their number does not match.
+//
+// It is called by generated code.
+func NewServiceDesc(protoName, serviceName string, sd protoreflect.ServiceDescriptor, functions ...string) *ServiceDesc {
+	mds := sd.Methods()
+	if mds.Len() != len(functions) {
+		panic(fmt.Sprintf("kitedesc: service %v has %d methods, but %d functions are given", sd.FullName(), mds.Len(), len(functions)))
+	}
+	desc := &ServiceDesc{
+		ProtoName:   protoName,
+		ServiceName: serviceName,
+		Descriptor:  sd,
+		Methods:     make([]MethodDesc, mds.Len()),
+	}
+	for i := 0; i < mds.Len(); i++ {
+		md := mds.Get(i)
+		desc.Methods[i] = MethodDesc{
+			Function:         functions[i],
+			FullName:         md.FullName(),
+			Input:            md.Input(),
+			Output:           md.Output(),
+			IdempotencyLevel: md.Options().(*descriptorpb.MethodOptions).GetIdempotencyLevel(),
+			Descriptor:       md,
+			Service:          desc,
+		}
+	}
+	return desc
+}
+
+// Method returns the description of the method served by the given kite
+// function, or nil if the service has no such method.
+func (s *ServiceDesc) Method(function string) *MethodDesc {
+	for i := range s.Methods {
+		if s.Methods[i].Function == function {
+			return &s.Methods[i]
+		}
+	}
+	return nil
+}
+
+var registry struct {
+	sync.RWMutex
+	services map[protoreflect.FullName]*ServiceDesc
+	methods  map[protoreflect.FullName]*MethodDesc
+}
+
+// Register registers the kite service sd, making it available to
+// FindServiceByName, FindMethodByName and RangeServices.
+// It panics if a service with the same full name is already registered.
+//
+// It is called by generated code.
+func Register(sd *ServiceDesc) {
+	name := sd.Descriptor.FullName()
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.services[name]; ok {
+		panic(fmt.Sprintf("kitedesc: service %v is already registered", name))
+	}
+	if registry.services == nil {
+		registry.services = make(map[protoreflect.FullName]*ServiceDesc)
+		registry.methods = make(map[protoreflect.FullName]*MethodDesc)
+	}
+	registry.services[name] = sd
+	for i := range sd.Methods {
+		registry.methods[sd.Methods[i].FullName] = &sd.Methods[i]
+	}
+}
+
+// FindServiceByName looks up a registered kite service by the full name
+// of its proto service (e.g., "pkg.Service").
+// It returns protoregistry.NotFound if the service is not registered.
+func FindServiceByName(name protoreflect.FullName) (*ServiceDesc, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	if sd, ok := registry.services[name]; ok {
+		return sd, nil
+	}
+	return nil, protoregistry.NotFound
+}
+
+// FindMethodByName looks up a method of a registered kite service by the full
+// name of its proto method (e.g., "pkg.Service.Method").
+// It returns protoregistry.NotFound if the method is not registered.
+func FindMethodByName(name protoreflect.FullName) (*MethodDesc, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	if md, ok := registry.methods[name]; ok {
+		return md, nil
+	}
+	return nil, protoregistry.NotFound
+}
+
+// RangeServices iterates over all registered kite services in an undefined
+// order while f returns true.
+func RangeServices(f func(*ServiceDesc) bool) {
+	registry.RLock()
+	sds := make([]*ServiceDesc, 0, len(registry.services))
+	for _, sd := range registry.services {
+		sds = append(sds, sd)
+	}
+	registry.RUnlock()
+	for _, sd := range sds {
+		if !f(sd) {
+			return
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kitedesc
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/reflect/protodesc"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	"github.com/golang/protobuf/protobuf/reflect/protoregistry"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+	"github.com/golang/protobuf/protobuf/types/known/emptypb"
+)
+
+// testService returns the descriptor of a service with two methods.
+func testService(t *testing.T) protoreflect.ServiceDescriptor {
+	t.Helper()
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("test.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request")}, {Name: proto.String("Response")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Users"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("get_user"),
+				InputType:  proto.String(".test.Request"),
+				OutputType: proto.String(".test.Response"),
+				Options:    &descriptorpb.MethodOptions{IdempotencyLevel: descriptorpb.MethodOptions_NO_SIDE_EFFECTS.Enum()},
+			}, {
+				Name:       proto.String("DeleteUser"),
+				InputType:  proto.String(".test.Request"),
+				OutputType: proto.String(".test.Response"),
+			}},
+		}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fd.Services().Get(0)
+}
+
+func TestNewServiceDesc(t *testing.T) {
+	sd := NewServiceDesc("test", "Users", testService(t), "GetUser", "DeleteUser")
+	if sd.ProtoName != "test" || sd.ServiceName != "Users" || sd.Descriptor.FullName() != "test.Users" {
+		t.Errorf("NewServiceDesc() = {%q, %q, %v}, want {test, Users, test.Users}", sd.ProtoName, sd.ServiceName, sd.Descriptor.FullName())
+	}
+
+	m := sd.Method("GetUser")
+	if m == nil {
+		t.Fatalf("Method(GetUser) = nil")
+	}
+	if m.FullName != "test.Users.get_user" || m.Input.FullName() != "test.Request" || m.Output.FullName() != "test.Response" {
+		t.Errorf("Method(GetUser) = {%v, %v, %v}, want {test.Users.get_user, test.Request, test.Response}", m.FullName, m.Input.FullName(), m.Output.FullName())
+	}
+	if !m.Idempotent() {
+		t.Errorf("Method(GetUser).Idempotent() = false, want true")
+	}
+	if sd.Method("DeleteUser").Idempotent() {
+		t.Errorf("Method(DeleteUser).Idempotent() = true, want false")
+	}
+	if m := sd.Method("get_user"); m != nil {
+		t.Errorf("Method(get_user) = %v, want nil", m.FullName)
+	}
+}
+
+func TestNewServiceDescMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewServiceDesc with too few functions did not panic")
+		}
+	}()
+	NewServiceDesc("test", "Users", testService(t), "GetUser")
+}
+
+func TestLoadService(t *testing.T) {
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("load.proto"),
+		Package:    proto.String("load"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto", "unregistered.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Loader"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Load"),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".unregistered.Response"),
+			}},
+		}},
+	}
+	rawDesc, err := proto.Marshal(fdp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sd := LoadService("load.Loader", rawDesc)
+	md := sd.Methods().Get(0)
+	if md.Input() != (&emptypb.Empty{}).ProtoReflect().Descriptor() {
+		t.Errorf("LoadService(load.Loader) input = %v, want the registered google.protobuf.Empty", md.Input().FullName())
+	}
+	if md.Output().FullName() != "unregistered.Response" || !md.Output().IsPlaceholder() {
+		t.Errorf("LoadService(load.Loader) output = %v, want a placeholder for unregistered.Response", md.Output().FullName())
+	}
+
+	registered := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("load_registered.proto"),
+		Package: proto.String("load"),
+		Syntax:  proto.String("proto3"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{Name: proto.String("Registered")}},
+	}
+	fd, err := protodesc.NewFile(registered, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := protoregistry.GlobalFiles.RegisterFile(fd); err != nil {
+		t.Fatal(err)
+	}
+	rawRegistered, err := proto.Marshal(registered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadService("load.Registered", rawRegistered); got != fd.Services().Get(0) {
+		t.Errorf("LoadService(load.Registered) does not return the descriptor registered with protoregistry.GlobalFiles")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("LoadService(load.Missing) did not panic")
+		}
+	}()
+	LoadService("load.Missing", rawDesc)
+}
+
+func TestRegistry(t *testing.T) {
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("registry.proto"),
+		Package:    proto.String("registry"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Pinger"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       proto.String("Ping"),
+				InputType:  proto.String(".google.protobuf.Empty"),
+				OutputType: proto.String(".google.protobuf.Empty"),
+			}},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sd := NewServiceDesc("registry", "Pinger", fd.Services().Get(0), "Ping")
+	Register(sd)
+
+	if got, err := FindServiceByName("registry.Pinger"); err != nil || got != sd {
+		t.Errorf("FindServiceByName(registry.Pinger) = %p, %v, want %p, nil", got, err, sd)
+	}
+	if _, err := FindServiceByName("registry.Missing"); err != protoregistry.NotFound {
+		t.Errorf("FindServiceByName(registry.Missing) error = %v, want %v", err, protoregistry.NotFound)
+	}
+
+	md, err := FindMethodByName("registry.Pinger.Ping")
+	if err != nil {
+		t.Fatalf("FindMethodByName(registry.Pinger.Ping) error = %v", err)
+	}
+	if md != sd.Method("Ping") || md.Service != sd {
+		t.Errorf("FindMethodByName(registry.Pinger.Ping) does not return the method of the registered service")
+	}
+	if _, err := FindMethodByName("registry.Pinger.Pong"); err != protoregistry.NotFound {
+		t.Errorf("FindMethodByName(registry.Pinger.Pong) error = %v, want %v", err, protoregistry.NotFound)
+	}
+
+	mt, err := md.InputType()
+	if err != nil {
+		t.Fatalf("InputType() error = %v", err)
+	}
+	if _, ok := mt.New().Interface().(*emptypb.Empty); !ok {
+		t.Errorf("InputType().New() = %T, want *emptypb.Empty", mt.New().Interface())
+	}
+
+	var found bool
+	RangeServices(func(s *ServiceDesc) bool {
+		found = s == sd
+		return !found
+	})
+	if !found {
+		t.Errorf("RangeServices did not visit registry.Pinger")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering registry.Pinger twice did not panic")
+		}
+	}()
+	Register(sd)
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/protobuf/encoding/protojson"
+	"github.com/golang/protobuf/protobuf/encoding/protowire"
+	"github.com/golang/protobuf/protobuf/internal/impl"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	hybridpb "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/hybrid"
+	opaquepb "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/opaque"
+)
+
+func TestOpaqueAPI(t *testing.T) {
+	m := opaquepb.Message_builder{
+		Name:  "name",
+		Count: proto.Int32(0),
+		Kind:  opaquepb.Enum_ENUM_ONE,
+		Tags:  []string{"a", "b"},
+		Children: map[string]*opaquepb.Message{
+			"c": opaquepb.Message_builder{Name: "child"}.Build(),
+		},
+		Text: proto.String("text"),
+	}.Build()
+
+	if got, want := m.GetName(), "name"; got != want {
+		t.Errorf("GetName() = %q, want %q", got, want)
+	}
+	if !m.HasCount() || m.GetCount() != 0 {
+		t.Errorf("HasCount(), GetCount() = %v, %v, want true, 0", m.HasCount(), m.GetCount())
+	}
+	if m.HasData() || m.HasChild() {
+		t.Errorf("HasData(), HasChild() = %v, %v, want false, false", m.HasData(), m.HasChild())
+	}
+	if got, want := m.WhichChoice(), opaquepb.Message_Text_case; got != want {
+		t.Errorf("WhichChoice() = %v, want %v", got, want)
+	}
+
+	m.SetData(nil)
+	if !m.HasData() || m.GetData() == nil {
+		t.Errorf("after SetData(nil): HasData() = %v, GetData() = %v, want true, []byte{}", m.HasData(), m.GetData())
+	}
+	m.ClearCount()
+	if m.HasCount() {
+		t.Errorf("after ClearCount(): HasCount() = true, want false")
+	}
+	m.SetNested(opaquepb.Message_builder{Name: "nested"}.Build())
+	if m.HasText() || !m.HasNested() || m.WhichChoice() != opaquepb.Message_Nested_case {
+		t.Errorf("after SetNested(): HasText(), HasNested(), WhichChoice() = %v, %v, %v, want false, true, %v",
+			m.HasText(), m.HasNested(), m.WhichChoice(), opaquepb.Message_Nested_case)
+	}
+	m.ClearText()
+	if !m.HasNested() {
+		t.Errorf("after ClearText(): HasNested() = false, want true")
+	}
+	m.SetNested(nil)
+	if m.HasChoice() || m.WhichChoice() != opaquepb.Message_Choice_not_set_case {
+		t.Errorf("after SetNested(nil): HasChoice(), WhichChoice() = %v, %v, want false, %v",
+			m.HasChoice(), m.WhichChoice(), opaquepb.Message_Choice_not_set_case)
+	}
+	m.SetValue(opaquepb.Enum_ENUM_ZERO)
+
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error: %v", err)
+	}
+	got := &opaquepb.Message{}
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatalf("proto.Unmarshal() error: %v", err)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("proto.Unmarshal(proto.Marshal(m)) = %v, want %v", got, m)
+	}
+	if !got.HasData() || !got.HasValue() || got.GetChildren()["c"].GetName() != "child" {
+		t.Errorf("proto.Unmarshal() = %v, want data, value and children to be set", got)
+	}
+
+	fields := m.ProtoReflect().Descriptor().Fields()
+	if !m.ProtoReflect().Has(fields.ByName("data")) || m.ProtoReflect().Has(fields.ByName("count")) {
+		t.Errorf("protoreflect presence of data and count does not match the Has methods")
+	}
+
+	j, err := protojson.Marshal(opaquepb.Message_builder{Name: "j", Count: proto.Int32(2)}.Build())
+	if err != nil {
+		t.Fatalf("protojson.Marshal() error: %v", err)
+	}
+	gotJSON := &opaquepb.Message{}
+	if err := protojson.Unmarshal(j, gotJSON); err != nil {
+		t.Fatalf("protojson.Unmarshal() error: %v", err)
+	}
+	if gotJSON.GetName() != "j" || gotJSON.GetCount() != 2 {
+		t.Errorf("protojson round trip = %v, want name j and count 2", gotJSON)
+	}
+
+	var nilMessage *opaquepb.Message
+	if nilMessage.HasCount() || nilMessage.HasChoice() || nilMessage.WhichChoice() != opaquepb.Message_Choice_not_set_case {
+		t.Errorf("presence methods of a nil message report a set field")
+	}
+}
+
+func TestHybridAPI(t *testing.T) {
+	m := hybridpb.Message_builder{
+		Name: "name",
+		Blob: []byte("blob"),
+	}.Build()
+	if m.Name != "name" {
+		t.Errorf("Name = %q, want %q", m.Name, "name")
+	}
+	if v, ok := m.Choice.(*hybridpb.Message_Blob); !ok || string(v.Blob) != "blob" {
+		t.Errorf("Choice = %v, want *Message_Blob", m.Choice)
+	}
+
+	m.SetCount(3)
+	if m.Count == nil || *m.Count != 3 {
+		t.Errorf("after SetCount(3): Count = %v, want 3", m.Count)
+	}
+	m.Choice = &hybridpb.Message_Text{Text: "text"}
+	if !m.HasText() || m.WhichChoice() != hybridpb.Message_Text_case {
+		t.Errorf("HasText(), WhichChoice() = %v, %v, want true, %v", m.HasText(), m.WhichChoice(), hybridpb.Message_Text_case)
+	}
+	m.ClearChoice()
+	if m.Choice != nil {
+		t.Errorf("after ClearChoice(): Choice = %v, want nil", m.Choice)
+	}
+}
+
+func TestLazyField(t *testing.T) {
+	m := opaquepb.Message_builder{
+		Name: "outer",
+		Payload: opaquepb.Message_builder{
+			Name:    "payload",
+			Payload: opaquepb.Message_builder{Name: "inner"}.Build(),
+		}.Build(),
+	}.Build()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("proto.Marshal() error: %v", err)
+	}
+	unmarshal := func(b []byte) *opaquepb.Message {
+		t.Helper()
+		got := &opaquepb.Message{}
+		if err := proto.Unmarshal(b, got); err != nil {
+			t.Fatalf("proto.Unmarshal() error: %v", err)
+		}
+		return got
+	}
+	fd := m.ProtoReflect().Descriptor().Fields().ByName("payload")
+
+	got := unmarshal(b)
+	if !impl.IsLazyField(got.ProtoReflect(), fd) {
+		t.Errorf("after proto.Unmarshal(): payload is not lazy")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, want := got.GetPayload().GetPayload().GetName(), "inner"; got != want {
+				t.Errorf("GetPayload().GetPayload().GetName() = %q, want %q", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+	if impl.IsLazyField(got.ProtoReflect(), fd) {
+		t.Errorf("after GetPayload(): payload is still lazy")
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("proto.Unmarshal(proto.Marshal(m)) = %v, want %v", got, m)
+	}
+
+	got = unmarshal(b)
+	if v := got.ProtoReflect().Get(fd).Message(); v.Interface().(*opaquepb.Message).GetName() != "payload" {
+		t.Errorf("protoreflect Get(payload) = %v, want name payload", v)
+	}
+	got = unmarshal(b)
+	if b2, err := proto.Marshal(got); err != nil || !proto.Equal(unmarshal(b2), m) {
+		t.Errorf("proto.Marshal() of a message with a lazy field = %v, %v, want %v", b2, err, m)
+	}
+	got = unmarshal(b)
+	got.SetPayload(nil)
+	if got.HasPayload() || impl.IsLazyField(got.ProtoReflect(), fd) {
+		t.Errorf("after SetPayload(nil): HasPayload() = true, want false")
+	}
+
+	// Several occurrences of the field are merged when it is decoded.
+	b2, _ := proto.Marshal(opaquepb.Message_builder{Payload: opaquepb.Message_builder{Count: proto.Int32(1)}.Build()}.Build())
+	got = unmarshal(append(append([]byte(nil), b...), b2...))
+	if p := got.GetPayload(); p.GetName() != "payload" || p.GetCount() != 1 {
+		t.Errorf("GetPayload() of merged occurrences = %v, want name payload and count 1", p)
+	}
+
+	// Invalid encodings are reported by Unmarshal, as for eager decoding.
+	for _, v := range [][]byte{
+		{0x0a, 0x05, 'a'},        // truncated name
+		{0x0a, 0x01, 0xff},       // invalid UTF-8 in name
+		{0x62, 0x02, 0x0a, 0x05}, // truncated name of nested payload
+	} {
+		b := protowire.AppendBytes(protowire.AppendTag(nil, 12, protowire.BytesType), v)
+		if err := proto.Unmarshal(b, &opaquepb.Message{}); err == nil {
+			t.Errorf("proto.Unmarshal(%x) succeeded, want error", b)
+		}
+	}
+
+	// The annotation has no effect on messages with exported struct fields.
+	h := &hybridpb.Message{}
+	if err := proto.Unmarshal(b, h); err != nil {
+		t.Fatalf("proto.Unmarshal() error: %v", err)
+	}
+	if h.Payload.GetName() != "payload" {
+		t.Errorf("hybrid Payload = %v, want name payload", h.Payload)
+	}
+}
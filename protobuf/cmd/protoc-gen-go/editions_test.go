@@ -0,0 +1,147 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gengo "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/internal_gengo"
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/reflect/protodesc"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+
+	editionspb "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/protoeditions"
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+	"github.com/golang/protobuf/protobuf/types/pluginpb"
+)
+
+// TestEditionsGolden regenerates the files in testdata/protoeditions from
+// their compiled descriptors and compares them with the checked-in output.
+//
+// The compiled descriptors do not retain source code info, so comments are
+// ignored by the comparison.
+func TestEditionsGolden(t *testing.T) {
+	files := []protoreflect.FileDescriptor{
+		editionspb.File_cmd_protoc_gen_go_testdata_protoeditions_enum_proto,
+		editionspb.File_cmd_protoc_gen_go_testdata_protoeditions_fields_proto,
+		editionspb.File_cmd_protoc_gen_go_testdata_protoeditions_nested_messages_proto,
+	}
+	gen, resp := generateEditions(t, files)
+	if resp.GetError() != "" {
+		t.Fatalf("generation failed: %v", resp.GetError())
+	}
+	if got, want := resp.GetSupportedFeatures()&uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS), uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS); got != want {
+		t.Errorf("supported features = %v, want FEATURE_SUPPORTS_EDITIONS to be set", resp.GetSupportedFeatures())
+	}
+	if got, want := descriptorpb.Edition(resp.GetMinimumEdition()), descriptorpb.Edition_EDITION_PROTO2; got != want {
+		t.Errorf("minimum edition = %v, want %v", got, want)
+	}
+	if got, want := descriptorpb.Edition(resp.GetMaximumEdition()), descriptorpb.Edition_EDITION_2023; got != want {
+		t.Errorf("maximum edition = %v, want %v", got, want)
+	}
+
+	generated := make(map[string]string)
+	for _, f := range resp.File {
+		generated[f.GetName()] = f.GetContent()
+	}
+	for _, fd := range files {
+		f := gen.FilesByPath[fd.Path()]
+		name := f.GeneratedFilenamePrefix + ".pb.go"
+		got, ok := generated[name]
+		if !ok {
+			t.Errorf("%v: no output generated", name)
+			continue
+		}
+		want, err := os.ReadFile(filepath.Join("testdata", "protoeditions", filepath.Base(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stripComments(got) != stripComments(string(want)) {
+			t.Errorf("%v: generated code does not match the golden file", name)
+		}
+	}
+
+	enums := generated[gen.FilesByPath[files[0].Path()].GeneratedFilenamePrefix+".pb.go"]
+	if got, want := strings.Count(enums, ") UnmarshalJSON(b []byte) error {"), 1; got != want {
+		t.Errorf("enum.pb.go has %d UnmarshalJSON methods, want %d", got, want)
+	}
+	if !strings.Contains(enums, "func (x *LegacyUnmarshalJSONTest) UnmarshalJSON(b []byte) error {") {
+		t.Errorf("enum.pb.go has no UnmarshalJSON method for LegacyUnmarshalJSONTest")
+	}
+	fields := generated[gen.FilesByPath[files[1].Path()].GeneratedFilenamePrefix+".pb.go"]
+	for _, want := range []string{
+		"OptionalInt32 *int32 `",
+		"RequiredInt32 *int32 `",
+		"RepeatedInt32 []int32 `",
+		"Optionalgroup *FieldTestMessage_OptionalGroup `protobuf:\"group,",
+	} {
+		if !strings.Contains(strings.Join(strings.Fields(fields), " "), want) {
+			t.Errorf("fields.pb.go does not contain %s", want)
+		}
+	}
+}
+
+// generateEditions runs the generator on files the way protoc would,
+// with the import paths of the runtime rewritten to this module.
+func generateEditions(t *testing.T, files []protoreflect.FileDescriptor) (*protogen.Plugin, *pluginpb.CodeGeneratorResponse) {
+	t.Helper()
+	req := &pluginpb.CodeGeneratorRequest{
+		Parameter: proto.String("Mtypes/gofeaturespb/go_features.proto=github.com/golang/protobuf/protobuf/types/gofeaturespb"),
+	}
+	seen := make(map[string]bool)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		for i, imports := 0, fd.Imports(); i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+	}
+	for _, fd := range files {
+		add(fd)
+		req.FileToGenerate = append(req.FileToGenerate, fd.Path())
+	}
+
+	// The golden files are generated without version markers and bson tags.
+	defer func(v bool) { gengo.GenerateVersionMarkers = v }(gengo.GenerateVersionMarkers)
+	defer func(v gengo.TagStyle) { gengo.StructTags.BSON = v }(gengo.StructTags.BSON)
+	gengo.GenerateVersionMarkers = false
+	gengo.StructTags.BSON = gengo.TagOmit
+
+	gen, err := protogen.Options{
+		ImportRewriteFunc: func(importPath protogen.GoImportPath) protogen.GoImportPath {
+			return protogen.GoImportPath(strings.Replace(string(importPath), "google.golang.org/protobuf", "github.com/golang/protobuf/protobuf", 1))
+		},
+	}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range req.FileToGenerate {
+		gengo.GenerateFile(gen, gen.FilesByPath[path])
+	}
+	gen.SupportedFeatures = gengo.SupportedFeatures
+	gen.SupportedEditionsMinimum = gengo.SupportedEditionsMinimum
+	gen.SupportedEditionsMaximum = gengo.SupportedEditionsMaximum
+	return gen, gen.Response()
+}
+
+// stripComments removes comment and blank lines from Go source.
+func stripComments(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed == "" || strings.HasPrefix(trimmed, "//") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,376 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/internal/genid"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+)
+
+// APILevel specifies the API of generated message types.
+type APILevel int
+
+const (
+	// APIOpen generates exported struct fields and getters.
+	APIOpen APILevel = iota
+	// APIHybrid generates exported struct fields as well as the
+	// accessor methods and builder of APIOpaque.
+	APIHybrid
+	// APIOpaque generates unexported struct fields, which are only
+	// accessed through Get, Set, Has and Clear methods, and a builder
+	// struct for constructing messages.
+	APIOpaque
+)
+
+var apiLevelNames = map[APILevel]string{
+	APIOpen:   "open",
+	APIHybrid: "hybrid",
+	APIOpaque: "opaque",
+}
+
+// String returns the parameter spelling of l.
+func (l APILevel) String() string {
+	if name, ok := apiLevelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("<unknown:%d>", int(l))
+}
+
+// Set parses an API level from its parameter spelling.
+// It implements flag.Value so that a level can be bound to a plugin parameter.
+func (l *APILevel) Set(v string) error {
+	for level, name := range apiLevelNames {
+		if name == v {
+			*l = level
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid API level %q: want one of open, hybrid, opaque", v)
+}
+
+// DefaultAPILevel specifies the API of generated message types.
+var DefaultAPILevel = APIOpen
+
+// MessageAPILevel reports the API level that message is generated with.
+//
+// Messages in the google.protobuf package always use APIOpen, since the
+// methods generated for the well-known types access their fields directly.
+func MessageAPILevel(message *protogen.Message) APILevel {
+	if message.Desc.ParentFile().Package() == "google.protobuf" {
+		return APIOpen
+	}
+	return DefaultAPILevel
+}
+
+// hiddenPrefix is prepended to the names of unexported struct fields
+// generated for APIOpaque.
+const hiddenPrefix = "xxx_hidden_"
+
+// structFieldName returns the name of the struct field holding field.
+func (m *messageInfo) structFieldName(field *protogen.Field) string {
+	switch {
+	case field.Desc.IsWeak():
+		return genid.WeakFieldPrefix_goname + field.GoName
+	case m.apiLevel == APIOpaque:
+		return hiddenPrefix + field.GoName
+	default:
+		return field.GoName
+	}
+}
+
+// isLazyField reports whether field is unmarshaled lazily.
+//
+// The runtime defers decoding a singular message field annotated with
+// [lazy = true] until it is first accessed, which requires all accesses to
+// go through methods. The annotation is therefore ignored for messages
+// with exported struct fields.
+func (m *messageInfo) isLazyField(field *protogen.Field) bool {
+	return m.apiLevel == APIOpaque &&
+		field.Desc.Options().(*descriptorpb.FieldOptions).GetLazy() &&
+		field.Desc.Kind() == protoreflect.MessageKind &&
+		field.Desc.Cardinality() == protoreflect.Optional &&
+		field.Oneof == nil &&
+		!field.Desc.IsWeak()
+}
+
+// genLazyFieldDecode generates a statement decoding the lazy field of x.
+func genLazyFieldDecode(g *protogen.GeneratedFile, field *protogen.Field) {
+	g.P("if x.", genid.LazyFields_goname, " != nil {")
+	g.P(protoimplPackage.Ident("X"), ".UnmarshalField(x, ", field.Desc.Number(), ")")
+	g.P("}")
+}
+
+// genLazyFieldClear generates a statement discarding the encoded value of
+// the lazy field of x.
+func genLazyFieldClear(g *protogen.GeneratedFile, field *protogen.Field) {
+	g.P("if x.", genid.LazyFields_goname, " != nil {")
+	g.P(protoimplPackage.Ident("X"), ".ClearLazyField(x, ", field.Desc.Number(), ")")
+	g.P("}")
+}
+
+// oneofFieldName returns the name of the struct field holding oneof.
+func (m *messageInfo) oneofFieldName(oneof *protogen.Oneof) string {
+	if m.apiLevel == APIOpaque {
+		return hiddenPrefix + oneof.GoName
+	}
+	return oneof.GoName
+}
+
+// oneofWrapperName returns the name of the oneof wrapper type of field.
+// The wrapper types of opaque messages are unexported.
+func (m *messageInfo) oneofWrapperName(field *protogen.Field) string {
+	name := field.GoIdent.GoName
+	if m.apiLevel == APIOpaque {
+		r, n := utf8.DecodeRuneInString(name)
+		name = string(unicode.ToLower(r)) + name[n:]
+	}
+	return name
+}
+
+// oneofCaseTypeName returns the name of the type of the field numbers
+// returned by the Which method of oneof.
+func oneofCaseTypeName(oneof *protogen.Oneof) string {
+	return "case_" + oneof.GoIdent.GoName
+}
+
+// builderName returns the name of the builder struct of m.
+func builderName(m *messageInfo) string {
+	return m.GoIdent.GoName + "_builder"
+}
+
+// genMessageAccessorMethods generates the Set, Has and Clear methods of the
+// fields of m and the Has, Clear and Which methods of its oneofs.
+func genMessageAccessorMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	for _, field := range m.Fields {
+		if field.Desc.IsWeak() {
+			continue
+		}
+		if oneof := field.Oneof; oneof != nil && oneof.Fields[0] == field && !oneof.Desc.IsSynthetic() {
+			genOneofAccessorMethods(g, f, m, oneof)
+		}
+		genFieldSetterMethod(g, f, m, field)
+		if field.Desc.HasPresence() {
+			genFieldPresenceMethods(g, f, m, field)
+		}
+	}
+}
+
+func genFieldSetterMethod(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, field *protogen.Field) {
+	goType, pointer := fieldGoType(g, f, field)
+	name := m.structFieldName(field)
+	g.AnnotateSymbol(m.GoIdent.GoName+".Set"+field.GoName, protogen.Annotation{
+		Location: field.Location,
+		Semantic: descriptorpb.GeneratedCodeInfo_Annotation_SET.Enum(),
+	})
+	leadingComments := appendDeprecationSuffix("",
+		field.Desc.ParentFile(),
+		field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated())
+	g.P(leadingComments, "func (x *", m.GoIdent, ") Set", field.GoName, "(v ", goType, ") {")
+	isBytes := field.Desc.Kind() == protoreflect.BytesKind && !field.Desc.IsList() && !field.Desc.IsMap()
+	switch {
+	case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
+		if field.Message != nil {
+			g.P("if v == nil {")
+			g.P("x.", m.oneofFieldName(field.Oneof), " = nil")
+			g.P("return")
+			g.P("}")
+		} else if isBytes {
+			// A nil slice would read back as an unset oneof.
+			g.P("if v == nil {")
+			g.P("v = []byte{}")
+			g.P("}")
+		}
+		g.P("x.", m.oneofFieldName(field.Oneof), " = &", m.oneofWrapperName(field), "{v}")
+	case m.isLazyField(field):
+		genLazyFieldClear(g, field)
+		g.P("x.", name, " = v")
+	case pointer:
+		g.P("x.", name, " = &v")
+	case isBytes && field.Desc.HasPresence():
+		// A nil slice would read back as an unset field.
+		g.P("if v == nil {")
+		g.P("v = []byte{}")
+		g.P("}")
+		g.P("x.", name, " = v")
+	default:
+		g.P("x.", name, " = v")
+	}
+	g.P("}")
+	g.P()
+}
+
+func genFieldPresenceMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, field *protogen.Field) {
+	inOneof := field.Oneof != nil && !field.Oneof.Desc.IsSynthetic()
+	leadingComments := appendDeprecationSuffix("",
+		field.Desc.ParentFile(),
+		field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated())
+
+	g.Annotate(m.GoIdent.GoName+".Has"+field.GoName, field.Location)
+	g.P(leadingComments, "func (x *", m.GoIdent, ") Has", field.GoName, "() bool {")
+	g.P("if x == nil {")
+	g.P("return false")
+	g.P("}")
+	if inOneof {
+		g.P("_, ok := x.", m.oneofFieldName(field.Oneof), ".(*", m.oneofWrapperName(field), ")")
+		g.P("return ok")
+	} else {
+		if m.isLazyField(field) {
+			genLazyFieldDecode(g, field)
+		}
+		g.P("return x.", m.structFieldName(field), " != nil")
+	}
+	g.P("}")
+	g.P()
+
+	g.AnnotateSymbol(m.GoIdent.GoName+".Clear"+field.GoName, protogen.Annotation{
+		Location: field.Location,
+		Semantic: descriptorpb.GeneratedCodeInfo_Annotation_SET.Enum(),
+	})
+	g.P(leadingComments, "func (x *", m.GoIdent, ") Clear", field.GoName, "() {")
+	if inOneof {
+		g.P("if _, ok := x.", m.oneofFieldName(field.Oneof), ".(*", m.oneofWrapperName(field), "); ok {")
+		g.P("x.", m.oneofFieldName(field.Oneof), " = nil")
+		g.P("}")
+	} else {
+		if m.isLazyField(field) {
+			genLazyFieldClear(g, field)
+		}
+		g.P("x.", m.structFieldName(field), " = nil")
+	}
+	g.P("}")
+	g.P()
+}
+
+func genOneofAccessorMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, oneof *protogen.Oneof) {
+	name := m.oneofFieldName(oneof)
+	caseType := oneofCaseTypeName(oneof)
+	notSet := oneof.GoIdent.GoName + "_not_set_case"
+
+	g.Annotate(m.GoIdent.GoName+".Has"+oneof.GoName, oneof.Location)
+	g.P("func (x *", m.GoIdent, ") Has", oneof.GoName, "() bool {")
+	g.P("if x == nil {")
+	g.P("return false")
+	g.P("}")
+	g.P("return x.", name, " != nil")
+	g.P("}")
+	g.P()
+
+	g.AnnotateSymbol(m.GoIdent.GoName+".Clear"+oneof.GoName, protogen.Annotation{
+		Location: oneof.Location,
+		Semantic: descriptorpb.GeneratedCodeInfo_Annotation_SET.Enum(),
+	})
+	g.P("func (x *", m.GoIdent, ") Clear", oneof.GoName, "() {")
+	g.P("x.", name, " = nil")
+	g.P("}")
+	g.P()
+
+	g.P("// ", caseType, " is the number of the field that is set in the ", oneof.Desc.Name(), " oneof")
+	g.P("// of ", m.GoIdent, ", or zero if none is set.")
+	g.P("type ", caseType, " ", protoreflectPackage.Ident("FieldNumber"))
+	g.P()
+	g.P("const (")
+	g.P(notSet, " ", caseType, " = 0")
+	for _, field := range oneof.Fields {
+		g.P(field.GoIdent.GoName, "_case ", caseType, " = ", field.Desc.Number())
+	}
+	g.P(")")
+	g.P()
+
+	g.Annotate(m.GoIdent.GoName+".Which"+oneof.GoName, oneof.Location)
+	g.P("func (x *", m.GoIdent, ") Which", oneof.GoName, "() ", caseType, " {")
+	g.P("if x == nil {")
+	g.P("return ", notSet)
+	g.P("}")
+	g.P("switch x.", name, ".(type) {")
+	for _, field := range oneof.Fields {
+		g.P("case *", m.oneofWrapperName(field), ":")
+		g.P("return ", field.GoIdent.GoName, "_case")
+	}
+	g.P("default:")
+	g.P("return ", notSet)
+	g.P("}")
+	g.P("}")
+	g.P()
+}
+
+// genMessageBuilder generates the builder struct of m and its Build method.
+//
+// The builder has an exported field for every field of m. A field of the
+// builder that has presence is only copied into the message if it is set.
+// If several fields of a oneof are set, the last one wins.
+func genMessageBuilder(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
+	builder := builderName(m)
+	g.P("type ", builder, " struct {")
+	g.P("_ [0]func() // Prohibits unkeyed literals.")
+	g.P()
+	var oneof *protogen.Oneof
+	for _, field := range m.Fields {
+		if field.Desc.IsWeak() {
+			continue
+		}
+		if field.Oneof != oneof && oneof != nil {
+			g.P("// -- end of ", oneof.Desc.Name())
+			oneof = nil
+		}
+		if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() && field.Oneof != oneof {
+			oneof = field.Oneof
+			g.P("// Fields of oneof ", oneof.Desc.Name(), ":")
+		}
+		goType := builderFieldGoType(g, f, field)
+		leadingComments := appendDeprecationSuffix(field.Comments.Leading,
+			field.Desc.ParentFile(),
+			field.Desc.Options().(*descriptorpb.FieldOptions).GetDeprecated())
+		g.P(leadingComments, field.GoName, " ", goType)
+	}
+	if oneof != nil {
+		g.P("// -- end of ", oneof.Desc.Name())
+	}
+	g.P("}")
+	g.P()
+
+	g.P("func (b0 ", builder, ") Build() *", m.GoIdent, " {")
+	g.P("m0 := &", m.GoIdent, "{}")
+	g.P("b, x := &b0, m0")
+	g.P("_, _ = b, x")
+	for _, field := range m.Fields {
+		if field.Desc.IsWeak() {
+			continue
+		}
+		if field.Oneof == nil || field.Oneof.Desc.IsSynthetic() {
+			g.P("x.", m.structFieldName(field), " = b.", field.GoName)
+			continue
+		}
+		value := "b." + field.GoName
+		if field.Message == nil && field.Desc.Kind() != protoreflect.BytesKind {
+			value = "*" + value
+		}
+		g.P("if b.", field.GoName, " != nil {")
+		g.P("x.", m.oneofFieldName(field.Oneof), " = &", m.oneofWrapperName(field), "{", value, "}")
+		g.P("}")
+	}
+	g.P("return m0")
+	g.P("}")
+	g.P()
+}
+
+// builderFieldGoType returns the Go type of the builder field of field.
+func builderFieldGoType(g *protogen.GeneratedFile, f *fileInfo, field *protogen.Field) string {
+	goType, pointer := fieldGoType(g, f, field)
+	if field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() {
+		// Fields of a oneof have presence, but are not stored as pointers.
+		pointer = field.Message == nil && field.Desc.Kind() != protoreflect.BytesKind
+	}
+	if pointer {
+		goType = "*" + goType
+	}
+	return goType
+}
@@ -25,6 +25,10 @@ type fileInfo struct {
 	allMessagesByPtr      map[*messageInfo]int // value is index into allMessages
 	allMessageFieldsByPtr map[*messageInfo]*structFields
 
+	// customTags holds the struct tags set by the (gotag.tags) option
+	// of each message field that has one.
+	customTags map[*protogen.Field]structTags
+
 	// needRawDesc specifies whether the generator should emit logic to provide
 	// the legacy raw descriptor in GZIP'd form.
 	// This is updated by enum and message generation logic as necessary,
@@ -125,6 +129,9 @@ type messageInfo struct {
 
 	isTracked bool
 	hasWeak   bool
+	hasLazy   bool
+
+	apiLevel APILevel
 }
 
 func newMessageInfo(f *fileInfo, message *protogen.Message) *messageInfo {
@@ -132,8 +139,10 @@ func newMessageInfo(f *fileInfo, message *protogen.Message) *messageInfo {
 	m.genRawDescMethod = true
 	m.genExtRangeMethod = true
 	m.isTracked = isTrackedMessage(m)
+	m.apiLevel = MessageAPILevel(message)
 	for _, field := range m.Fields {
 		m.hasWeak = m.hasWeak || field.Desc.IsWeak()
+		m.hasLazy = m.hasLazy || m.isLazyField(field)
 	}
 	return m
 }
@@ -29,7 +29,14 @@ import (
 )
 
 // SupportedFeatures reports the set of supported protobuf language features.
-var SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+var SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL | pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS)
+
+// SupportedEditionsMinimum and SupportedEditionsMaximum are the oldest and
+// newest editions supported by the generator.
+var (
+	SupportedEditionsMinimum = descriptorpb.Edition_EDITION_PROTO2
+	SupportedEditionsMaximum = descriptorpb.Edition_EDITION_2023
+)
 
 // GenerateVersionMarkers specifies whether to generate version markers.
 var GenerateVersionMarkers = true
@@ -70,6 +77,9 @@ func GenerateFile(gen *protogen.Plugin, file *protogen.File) *protogen.Generated
 	filename := file.GeneratedFilenamePrefix + ".pb.go"
 	g := gen.NewGeneratedFile(filename, file.GoImportPath)
 	f := newFileInfo(file)
+	if err := initCustomTags(f); err != nil {
+		gen.Error(err)
+	}
 
 	genStandaloneComments(g, f, int32(genid.FileDescriptorProto_Syntax_field_number))
 	genGeneratedHeader(gen, g, f)
@@ -360,6 +370,10 @@ func genMessageInternalFields(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P(genid.WeakFields_goname, " ", protoimplPackage.Ident("WeakFields"))
 		sf.append(genid.WeakFields_goname)
 	}
+	if m.hasLazy {
+		g.P(genid.LazyFields_goname, " ", protoimplPackage.Ident("LazyFields"))
+		sf.append(genid.LazyFields_goname)
+	}
 	g.P(genid.UnknownFields_goname, " ", protoimplPackage.Ident("UnknownFields"))
 	sf.append(genid.UnknownFields_goname)
 	if m.Desc.ExtensionRanges().Len() > 0 {
@@ -388,19 +402,22 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 			tags = append(tags, gotrackTags...)
 		}
 
-		g.Annotate(m.GoIdent.GoName+"."+oneof.GoName, oneof.Location)
+		name := m.oneofFieldName(oneof)
+		g.Annotate(m.GoIdent.GoName+"."+name, oneof.Location)
 		leadingComments := oneof.Comments.Leading
-		if leadingComments != "" {
-			leadingComments += "\n"
-		}
-		ss := []string{fmt.Sprintf(" Types that are assignable to %s:\n", oneof.GoName)}
-		for _, field := range oneof.Fields {
-			ss = append(ss, "\t*"+field.GoIdent.GoName+"\n")
+		if m.apiLevel != APIOpaque {
+			if leadingComments != "" {
+				leadingComments += "\n"
+			}
+			ss := []string{fmt.Sprintf(" Types that are assignable to %s:\n", oneof.GoName)}
+			for _, field := range oneof.Fields {
+				ss = append(ss, "\t*"+field.GoIdent.GoName+"\n")
+			}
+			leadingComments += protogen.Comments(strings.Join(ss, ""))
 		}
-		leadingComments += protogen.Comments(strings.Join(ss, ""))
 		g.P(leadingComments,
-			oneof.GoName, " ", oneofInterfaceName(oneof), tags)
-		sf.append(oneof.GoName)
+			name, " ", oneofInterfaceName(oneof), tags)
+		sf.append(name)
 		return
 	}
 	goType, pointer := fieldGoType(g, f, field)
@@ -408,47 +425,13 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 		goType = "*" + goType
 	}
 
-	//jsonUnderscoreName := func(name string) string {
-	//	// 使用 strings.Builder 代替自定义的 NewBuffer
-	//	var buffer strings.Builder
-	//	for i, r := range name {
-	//		if unicode.IsUpper(r) {
-	//			if i != 0 {
-	//				buffer.WriteRune('_') // 使用 WriteRune 追加下划线字符
-	//			}
-	//			buffer.WriteRune(unicode.ToLower(r)) // 将大写字母转为小写
-	//		} else {
-	//			buffer.WriteRune(r) // 保持非大写字母原样追加
-	//		}
-	//	}
-	//
-	//	return buffer.String()
-	//}
-	bsonUnderscoreName := func(name string) string {
-		if strings.ToLower(name) == "id" {
-			return "_id"
-		}
-
-		var buffer strings.Builder
-		for i, r := range name {
-			if unicode.IsUpper(r) {
-				if i != 0 {
-					buffer.WriteRune('_') // 使用 WriteRune 追加下划线字符
-				}
-				buffer.WriteRune(unicode.ToLower(r)) // 将大写字母转为小写
-			} else {
-				buffer.WriteRune(r) // 保持非大写字母原样追加
-			}
-		}
-
-		return buffer.String()
-	}
-
 	tags := structTags{
 		{"protobuf", fieldProtobufTagValue(field)},
-		{"json", fieldJSONTagValue(field)},
-		//{"json", jsonUnderscoreName(string(field.Desc.Name())) + ",omitempty"},
-		{"bson", bsonUnderscoreName(string(field.Desc.Name()))},
+	}
+	if m.apiLevel != APIOpaque {
+		// Unexported fields are ignored by encoders relying on struct tags.
+		tags = append(tags, fieldNamingTags(field)...)
+		tags = mergeCustomTags(tags, f.customTags[field])
 	}
 	if field.Desc.IsMap() {
 		key := field.Message.Fields[0]
@@ -462,10 +445,7 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 		tags = append(tags, gotrackTags...)
 	}
 
-	name := field.GoName
-	if field.Desc.IsWeak() {
-		name = genid.WeakFieldPrefix_goname + name
-	}
+	name := m.structFieldName(field)
 	g.Annotate(m.GoIdent.GoName+"."+name, field.Location)
 	leadingComments := appendDeprecationSuffix(field.Comments.Leading,
 		field.Desc.ParentFile(),
@@ -473,7 +453,7 @@ func genMessageField(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, fie
 	g.P(leadingComments,
 		name, " ", goType, tags,
 		trailingComment(field.Comments.Trailing))
-	sf.append(field.GoName)
+	sf.append(name)
 }
 
 // genMessageDefaultDecls generates consts and vars holding the default
@@ -546,6 +526,10 @@ func genMessageMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
 	genMessageBaseMethods(g, f, m)
 	genMessageGetterMethods(g, f, m)
 	genMessageSetterMethods(g, f, m)
+	if m.apiLevel != APIOpen {
+		genMessageAccessorMethods(g, f, m)
+		genMessageBuilder(g, f, m)
+	}
 }
 
 func genMessageBaseMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo) {
@@ -593,7 +577,8 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 		genNoInterfacePragma(g, m.isTracked)
 
 		// Getter for parent oneof.
-		if oneof := field.Oneof; oneof != nil && oneof.Fields[0] == field && !oneof.Desc.IsSynthetic() {
+		// Opaque messages do not expose the oneof wrapper types.
+		if oneof := field.Oneof; oneof != nil && oneof.Fields[0] == field && !oneof.Desc.IsSynthetic() && m.apiLevel != APIOpaque {
 			g.Annotate(m.GoIdent.GoName+".Get"+oneof.GoName, oneof.Location)
 			g.P("func (m *", m.GoIdent.GoName, ") Get", oneof.GoName, "() ", oneofInterfaceName(oneof), " {")
 			g.P("if m != nil {")
@@ -623,6 +608,15 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 			g.P("}")
 			g.P("return ", protoimplPackage.Ident("X"), ".GetWeak(w, ", field.Desc.Number(), ", ", strconv.Quote(string(field.Message.Desc.FullName())), ")")
 			g.P("}")
+		case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic() && m.apiLevel == APIOpaque:
+			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", goType, " {")
+			g.P("if x != nil {")
+			g.P("if x, ok := x.", m.oneofFieldName(field.Oneof), ".(*", m.oneofWrapperName(field), "); ok {")
+			g.P("return x.", field.GoName)
+			g.P("}")
+			g.P("}")
+			g.P("return ", defaultValue)
+			g.P("}")
 		case field.Oneof != nil && !field.Oneof.Desc.IsSynthetic():
 			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", goType, " {")
 			g.P("if x, ok := x.Get", field.Oneof.GoName, "().(*", field.GoIdent, "); ok {")
@@ -630,18 +624,26 @@ func genMessageGetterMethods(g *protogen.GeneratedFile, f *fileInfo, m *messageI
 			g.P("}")
 			g.P("return ", defaultValue)
 			g.P("}")
+		case m.isLazyField(field):
+			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", goType, " {")
+			g.P("if x != nil {")
+			genLazyFieldDecode(g, field)
+			g.P("return x.", m.structFieldName(field))
+			g.P("}")
+			g.P("return ", defaultValue)
+			g.P("}")
 		default:
 			g.P(leadingComments, "func (x *", m.GoIdent, ") Get", field.GoName, "() ", goType, " {")
 			if !field.Desc.HasPresence() || defaultValue == "nil" {
 				g.P("if x != nil {")
 			} else {
-				g.P("if x != nil && x.", field.GoName, " != nil {")
+				g.P("if x != nil && x.", m.structFieldName(field), " != nil {")
 			}
 			star := ""
 			if pointer {
 				star = "*"
 			}
-			g.P("return ", star, " x.", field.GoName)
+			g.P("return ", star, " x.", m.structFieldName(field))
 			g.P("}")
 			g.P("return ", defaultValue)
 			g.P("}")
@@ -766,10 +768,6 @@ func fieldDefaultValue(g *protogen.GeneratedFile, f *fileInfo, m *messageInfo, f
 	}
 }
 
-func fieldJSONTagValue(field *protogen.Field) string {
-	return string(field.Desc.Name()) + ",omitempty"
-}
-
 func genExtensions(g *protogen.GeneratedFile, f *fileInfo) {
 	if len(f.allExtensions) == 0 {
 		return
@@ -850,13 +848,15 @@ func genMessageOneofWrapperTypes(g *protogen.GeneratedFile, f *fileInfo, m *mess
 		g.P("}")
 		g.P()
 		for _, field := range oneof.Fields {
-			g.Annotate(field.GoIdent.GoName, field.Location)
-			g.Annotate(field.GoIdent.GoName+"."+field.GoName, field.Location)
-			g.P("type ", field.GoIdent, " struct {")
+			name := m.oneofWrapperName(field)
+			g.Annotate(name, field.Location)
+			g.Annotate(name+"."+field.GoName, field.Location)
+			g.P("type ", name, " struct {")
 			goType, _ := fieldGoType(g, f, field)
 			tags := structTags{
 				{"protobuf", fieldProtobufTagValue(field)},
 			}
+			tags = mergeCustomTags(tags, f.customTags[field])
 			if m.isTracked {
 				tags = append(tags, gotrackTags...)
 			}
@@ -870,7 +870,7 @@ func genMessageOneofWrapperTypes(g *protogen.GeneratedFile, f *fileInfo, m *mess
 			g.P()
 		}
 		for _, field := range oneof.Fields {
-			g.P("func (*", field.GoIdent, ") ", ifName, "() {}")
+			g.P("func (*", m.oneofWrapperName(field), ") ", ifName, "() {}")
 			g.P()
 		}
 	}
@@ -0,0 +1,310 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	"github.com/golang/protobuf/protoc-gen-go/gotag"
+)
+
+// TagStyle specifies how the name in a generated struct tag is derived
+// from the name of a proto field.
+type TagStyle int
+
+const (
+	// TagOmit generates no tag at all.
+	TagOmit TagStyle = iota
+	// TagProto uses the field name as declared in the .proto file.
+	TagProto
+	// TagSnake uses the snake_case form of the field name.
+	TagSnake
+	// TagCamel uses the lowerCamelCase JSON name of the field.
+	TagCamel
+)
+
+var tagStyleNames = map[TagStyle]string{
+	TagOmit:  "omit",
+	TagProto: "proto",
+	TagSnake: "snake",
+	TagCamel: "camel",
+}
+
+// String returns the parameter spelling of s.
+func (s TagStyle) String() string {
+	if name, ok := tagStyleNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("<unknown:%d>", int(s))
+}
+
+// Set parses a tag style from its parameter spelling.
+// Both "omit" and "none" select TagOmit.
+// It implements flag.Value so that a style can be bound to a plugin parameter.
+func (s *TagStyle) Set(v string) error {
+	if v == "none" {
+		*s = TagOmit
+		return nil
+	}
+	for style, name := range tagStyleNames {
+		if name == v {
+			*s = style
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid tag style %q: want one of snake, camel, proto, omit", v)
+}
+
+// ExtraTag is an additional struct tag key generated for every message field.
+type ExtraTag struct {
+	Key   string
+	Style TagStyle
+}
+
+// ExtraTags is a list of additional struct tags.
+// It implements flag.Value, where each call to Set appends a single tag
+// given in the form "key" or "key:style". The style defaults to snake.
+type ExtraTags []ExtraTag
+
+func (ts *ExtraTags) String() string {
+	if ts == nil {
+		return ""
+	}
+	var ss []string
+	for _, t := range *ts {
+		ss = append(ss, t.Key+":"+t.Style.String())
+	}
+	return strings.Join(ss, "+")
+}
+
+func (ts *ExtraTags) Set(v string) error {
+	t := ExtraTag{Key: v, Style: TagSnake}
+	if i := strings.IndexByte(v, ':'); i >= 0 {
+		t.Key = v[:i]
+		if err := t.Style.Set(v[i+1:]); err != nil {
+			return err
+		}
+	}
+	if !isValidTagKey(t.Key) {
+		return fmt.Errorf("invalid struct tag key %q", t.Key)
+	}
+	switch t.Key {
+	case "protobuf", "protobuf_key", "protobuf_val", "protobuf_oneof", "json", "bson":
+		return fmt.Errorf("struct tag key %q is generated by protoc-gen-go and cannot be added", t.Key)
+	}
+	for _, t2 := range *ts {
+		if t2.Key == t.Key {
+			return fmt.Errorf("duplicate struct tag key %q", t.Key)
+		}
+	}
+	*ts = append(*ts, t)
+	return nil
+}
+
+// isValidTagKey reports whether s is usable as a struct tag key
+// per the conventions of the reflect.StructTag.Get method.
+func isValidTagKey(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r <= ' ' || r == ':' || r == '"' || r == '`' || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// StructTagOptions controls the struct tags generated for message fields
+// in addition to the protobuf tags.
+type StructTagOptions struct {
+	JSON  TagStyle
+	BSON  TagStyle
+	Extra ExtraTags
+}
+
+// StructTags specifies the struct tags to generate for message fields.
+// The defaults match the historical output of this generator.
+var StructTags = StructTagOptions{
+	JSON: TagProto,
+	BSON: TagSnake,
+}
+
+// fieldNamingTags returns the json, bson, and extra struct tags for field.
+func fieldNamingTags(field *protogen.Field) structTags {
+	var tags structTags
+	if name := tagName(field, "json", StructTags.JSON); name != "" {
+		tags = append(tags, [2]string{"json", name + ",omitempty"})
+	}
+	if name := tagName(field, "bson", StructTags.BSON); name != "" {
+		tags = append(tags, [2]string{"bson", name})
+	}
+	for _, t := range StructTags.Extra {
+		if name := tagName(field, t.Key, t.Style); name != "" {
+			tags = append(tags, [2]string{t.Key, name})
+		}
+	}
+	return tags
+}
+
+// tagName returns the name used for the field in a struct tag with the given
+// key and style. It returns the empty string if no tag should be generated.
+func tagName(field *protogen.Field, key string, style TagStyle) string {
+	name := string(field.Desc.Name())
+	switch style {
+	case TagProto:
+		return name
+	case TagSnake:
+		// MongoDB stores the primary key of a document in the "_id" field.
+		if key == "bson" && strings.ToLower(name) == "id" {
+			return "_id"
+		}
+		return snakeCase(name)
+	case TagCamel:
+		return field.Desc.JSONName()
+	default:
+		return ""
+	}
+}
+
+// snakeCase converts an identifier to snake_case by lowering every upper-case
+// letter and prefixing it with an underscore, unless it is the first letter.
+func snakeCase(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i != 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// initCustomTags collects the (gotag.tags) options of all message fields in f.
+func initCustomTags(f *fileInfo) error {
+	for _, m := range f.allMessages {
+		for _, field := range m.Fields {
+			tags, err := fieldCustomTags(field)
+			if err != nil {
+				return err
+			}
+			if tags == nil {
+				continue
+			}
+			if f.customTags == nil {
+				f.customTags = make(map[*protogen.Field]structTags)
+			}
+			f.customTags[field] = tags
+		}
+	}
+	return nil
+}
+
+// fieldCustomTags returns the struct tags set by the (gotag.tags) option of field.
+func fieldCustomTags(field *protogen.Field) (structTags, error) {
+	s := proto.GetExtension(field.Desc.Options(), gotag.E_Tags).(string)
+	if s == "" {
+		return nil, nil
+	}
+	tags, err := parseStructTags(s)
+	if err != nil {
+		return nil, fmt.Errorf("%v: invalid (gotag.tags) option: %v", field.Desc.FullName(), err)
+	}
+	for _, t := range tags {
+		switch t[0] {
+		case "protobuf", "protobuf_key", "protobuf_val", "protobuf_oneof", "go":
+			return nil, fmt.Errorf("%v: struct tag key %q in (gotag.tags) option is reserved by protoc-gen-go", field.Desc.FullName(), t[0])
+		}
+	}
+	return tags, nil
+}
+
+// parseStructTags parses s in the conventional struct tag format,
+// which is a space-separated list of key:"value" pairs.
+func parseStructTags(s string) (structTags, error) {
+	var tags structTags
+	for {
+		s = strings.TrimLeft(s, " ")
+		if s == "" {
+			return tags, nil
+		}
+		i := strings.IndexByte(s, ':')
+		if i < 0 || !isValidTagKey(s[:i]) {
+			return nil, fmt.Errorf("malformed tag at %q", s)
+		}
+		key := s[:i]
+		s = s[i+1:]
+		if s == "" || s[0] != '"' {
+			return nil, fmt.Errorf("missing quoted value for key %q", key)
+		}
+		// Scan to the closing quote, skipping escaped characters.
+		j := 1
+		for j < len(s) && s[j] != '"' {
+			if s[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(s) {
+			return nil, fmt.Errorf("unterminated value for key %q", key)
+		}
+		val, err := strconv.Unquote(s[:j+1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for key %q: %v", key, err)
+		}
+		s = s[j+1:]
+		for _, t := range tags {
+			if t[0] == key {
+				return nil, fmt.Errorf("duplicate key %q", key)
+			}
+		}
+		tags = append(tags, [2]string{key, val})
+	}
+}
+
+// mergeCustomTags merges the custom tags into tags.
+//
+// A custom tag replaces a generated tag with the same key in place,
+// a custom tag with an empty value removes it, and any other custom tag is
+// appended.
+func mergeCustomTags(tags, custom structTags) structTags {
+	if len(custom) == 0 {
+		return tags
+	}
+	var merged structTags
+	for _, t := range tags {
+		for _, c := range custom {
+			if c[0] == t[0] {
+				t = c
+				break
+			}
+		}
+		if t[1] != "" {
+			merged = append(merged, t)
+		}
+	}
+next:
+	for _, c := range custom {
+		for _, t := range tags {
+			if c[0] == t[0] {
+				continue next
+			}
+		}
+		if c[1] != "" {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
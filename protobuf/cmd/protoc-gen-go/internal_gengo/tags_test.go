@@ -0,0 +1,198 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal_gengo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	"github.com/golang/protobuf/protobuf/types/descriptorpb"
+	"github.com/golang/protobuf/protobuf/types/pluginpb"
+	"github.com/golang/protobuf/protoc-gen-go/gotag"
+)
+
+func TestStructTags(t *testing.T) {
+	tests := []struct {
+		desc string
+		opts StructTagOptions
+		want []string
+	}{{
+		desc: "default",
+		opts: StructTags,
+		want: []string{
+			`json:"id,omitempty" bson:"_id"`,
+			`json:"userName,omitempty" bson:"user_name"`,
+			`json:"display_name,omitempty" bson:"display_name"`,
+		},
+	}, {
+		desc: "snake json, proto bson",
+		opts: StructTagOptions{JSON: TagSnake, BSON: TagProto},
+		want: []string{
+			`json:"id,omitempty" bson:"id"`,
+			`json:"user_name,omitempty" bson:"userName"`,
+			`json:"display_name,omitempty" bson:"display_name"`,
+		},
+	}, {
+		desc: "camel json, no bson, extra tags",
+		opts: StructTagOptions{
+			JSON:  TagCamel,
+			BSON:  TagOmit,
+			Extra: ExtraTags{{"db", TagSnake}, {"yaml", TagProto}},
+		},
+		want: []string{
+			`json:"id,omitempty" db:"id" yaml:"id"`,
+			`json:"userName,omitempty" db:"user_name" yaml:"userName"`,
+			`json:"displayName,omitempty" db:"display_name" yaml:"display_name"`,
+		},
+	}, {
+		desc: "omitted json",
+		opts: StructTagOptions{JSON: TagOmit, BSON: TagSnake},
+		want: []string{
+			`protobuf:"bytes,1,opt,name=id,proto3" bson:"_id"`,
+			`protobuf:"bytes,2,opt,name=userName,proto3" bson:"user_name"`,
+		},
+	}}
+
+	saved := StructTags
+	defer func() { StructTags = saved }()
+	for _, tt := range tests {
+		StructTags = tt.opts
+		got := generateTestFile(t, &descriptorpb.FileDescriptorProto{
+			Name:    proto.String("tags.proto"),
+			Package: proto.String("tags"),
+			Syntax:  proto.String("proto3"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/tags")},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("User"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					stringField("id", 1),
+					stringField("userName", 2),
+					stringField("display_name", 3),
+				},
+			}},
+		})
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: generated code does not contain %s", tt.desc, want)
+			}
+		}
+	}
+}
+
+func TestTagStyleSet(t *testing.T) {
+	for _, v := range []string{"snake", "camel", "proto", "omit", "none"} {
+		var s TagStyle
+		if err := s.Set(v); err != nil {
+			t.Errorf("Set(%q): %v", v, err)
+		}
+	}
+	var s TagStyle
+	if err := s.Set("kebab"); err == nil {
+		t.Errorf("Set(%q): want error, got nil", "kebab")
+	}
+}
+
+func TestExtraTagsSet(t *testing.T) {
+	var ts ExtraTags
+	for _, v := range []string{"db", "yaml:camel", "gorm:proto"} {
+		if err := ts.Set(v); err != nil {
+			t.Fatalf("Set(%q): %v", v, err)
+		}
+	}
+	if got, want := ts.String(), "db:snake+yaml:camel+gorm:proto"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	for _, v := range []string{"db", "json", "bson:snake", "", "a b", "db2:kebab"} {
+		if err := ts.Set(v); err == nil {
+			t.Errorf("Set(%q): want error, got nil", v)
+		}
+	}
+}
+
+func TestCustomStructTags(t *testing.T) {
+	withTags := func(fd *descriptorpb.FieldDescriptorProto, tags string) *descriptorpb.FieldDescriptorProto {
+		fd.Options = &descriptorpb.FieldOptions{}
+		proto.SetExtension(fd.Options, gotag.E_Tags, tags)
+		return fd
+	}
+	file := func(fields ...*descriptorpb.FieldDescriptorProto) *descriptorpb.FileDescriptorProto {
+		return &descriptorpb.FileDescriptorProto{
+			Name:    proto.String("tags.proto"),
+			Package: proto.String("tags"),
+			Syntax:  proto.String("proto3"),
+			Options: &descriptorpb.FileOptions{GoPackage: proto.String("example.com/tags")},
+			MessageType: []*descriptorpb.DescriptorProto{{
+				Name:  proto.String("User"),
+				Field: fields,
+			}},
+		}
+	}
+
+	got := generateTestFile(t, file(
+		withTags(stringField("user_id", 1), `db:"uid" bson:"_id"`),
+		withTags(stringField("name", 2), `validate:"required,max=64" json:"-"`),
+		withTags(stringField("secret", 3), `bson:""`),
+	))
+	for _, want := range []string{
+		`protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty" bson:"_id" db:"uid"`,
+		`protobuf:"bytes,2,opt,name=name,proto3" json:"-" bson:"name" validate:"required,max=64"`,
+		`protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"` + "`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("generated code does not contain %s", want)
+		}
+	}
+
+	for _, tags := range []string{
+		`protobuf:"bytes,1"`,
+		`go:"track"`,
+		`db:"a" db:"b"`,
+		`db:uid`,
+		`db:"uid`,
+		`db`,
+	} {
+		gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+			FileToGenerate: []string{"tags.proto"},
+			ProtoFile:      []*descriptorpb.FileDescriptorProto{file(withTags(stringField("id", 1), tags))},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		GenerateFile(gen, gen.Files[0])
+		if gen.Response().Error == nil {
+			t.Errorf("(gotag.tags) = %q: want error, got nil", tags)
+		}
+	}
+}
+
+func stringField(name string, num int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{
+		Name:   proto.String(name),
+		Number: proto.Int32(num),
+		Label:  descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:   descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+	}
+}
+
+// generateTestFile runs the generator on fd and returns the generated source.
+func generateTestFile(t *testing.T, fd *descriptorpb.FileDescriptorProto) string {
+	t.Helper()
+	gen, err := protogen.Options{}.New(&pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{fd.GetName()},
+		ProtoFile:      []*descriptorpb.FileDescriptorProto{fd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := GenerateFile(gen, gen.Files[0])
+	b, err := g.Content()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -326,6 +326,21 @@ func genPackageKnownComment(f *fileInfo) protogen.Comments {
  IsValid needs to be passed the target message type as an input since the
  FieldMask message itself does not store the message type that the set of paths
  are for.
+
+
+ Applying a FieldMask
+
+ The Project function returns a copy of a message holding only the fields
+ selected by a FieldMask, and the MergeMasked function applies the selected
+ fields of one message to another, as in an update operation:
+
+	var resource, update *descriptorpb.DescriptorProto
+	if err := fieldmaskpb.MergeMasked(resource, update, fm); err != nil {
+		... // handle error
+	}
+
+ To encode only the selected fields of a message, without copying it,
+ set the Mask option of proto.MarshalOptions to proto.NewMask(fm.GetPaths()).
 `
 	default:
 		return ""
@@ -1050,6 +1065,198 @@ func genMessageKnownFunctions(g *protogen.GeneratedFile, f *fileInfo, m *message
 		g.P("}")
 		g.P()
 
+		g.P("// Project returns a copy of m which holds only the fields selected by")
+		g.P("// the paths of the mask, which must be valid for the message type of m.")
+		g.P("// A nil or empty mask selects no fields.")
+		g.P("func Project(m ", protoPackage.Ident("Message"), ", mask *FieldMask) (", protoPackage.Ident("Message"), ", error) {")
+		g.P("	if err := checkPaths(m, mask.GetPaths()); err != nil {")
+		g.P("		return nil, err")
+		g.P("	}")
+		g.P("	src := m.ProtoReflect()")
+		g.P("	dst := src.New()")
+		g.P("	projectMessage(dst, src, newPathTree(mask.GetPaths()))")
+		g.P("	return dst.Interface(), nil")
+		g.P("}")
+		g.P()
+		g.P("func projectMessage(dst, src ", protoreflectPackage.Ident("Message"), ", t pathTree) {")
+		g.P("	src.Range(func(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ") bool {")
+		g.P("		sub, ok := t.field(fd)")
+		g.P("		switch {")
+		g.P("		case !ok:")
+		g.P("		case sub == nil:")
+		g.P("			mergeField(dst, fd, v)")
+		g.P("		default:")
+		g.P("			projectMessage(dst.Mutable(fd).Message(), v.Message(), sub)")
+		g.P("		}")
+		g.P("		return true")
+		g.P("	})")
+		g.P("}")
+		g.P()
+		g.P("// MergeOptions configures MergeMasked.")
+		g.P("type MergeOptions struct {")
+		g.P("	// ReplaceRepeated specifies that repeated and map fields selected by")
+		g.P("	// the mask are replaced by those of the source message.")
+		g.P("	// By default, elements of the source are appended to repeated fields,")
+		g.P("	// and entries of the source are added to maps, replacing those with")
+		g.P("	// the same key.")
+		g.P("	ReplaceRepeated bool")
+		g.P()
+		g.P("	// ReplaceMessages specifies that message fields named by the last")
+		g.P("	// element of a path are replaced by those of the source message.")
+		g.P("	// By default, they are merged into the destination message.")
+		g.P("	ReplaceMessages bool")
+		g.P("}")
+		g.P()
+		g.P("// MergeMasked updates dst with the fields of src selected by the paths of")
+		g.P("// the mask, as described for update operations in the documentation of")
+		g.P("// FieldMask. It is equivalent to MergeOptions{}.MergeMasked(dst, src, mask).")
+		g.P("func MergeMasked(dst, src ", protoPackage.Ident("Message"), ", mask *FieldMask) error {")
+		g.P("	return MergeOptions{}.MergeMasked(dst, src, mask)")
+		g.P("}")
+		g.P()
+		g.P("// MergeMasked updates dst with the fields of src selected by the paths of")
+		g.P("// the mask, which must be valid for the message type of dst and src.")
+		g.P("// A selected field is reset in dst if it is not populated in src.")
+		g.P("// Fields which are not selected are left untouched.")
+		g.P("// A nil or empty mask selects no fields.")
+		g.P("func (o MergeOptions) MergeMasked(dst, src ", protoPackage.Ident("Message"), ", mask *FieldMask) error {")
+		g.P("	dstMsg, srcMsg := dst.ProtoReflect(), src.ProtoReflect()")
+		g.P("	if dstMsg.Descriptor().FullName() != srcMsg.Descriptor().FullName() {")
+		g.P("		return ", protoimplPackage.Ident("X"), ".NewError(\"mismatching message types %q and %q\", dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName())")
+		g.P("	}")
+		g.P("	if err := checkPaths(dst, mask.GetPaths()); err != nil {")
+		g.P("		return err")
+		g.P("	}")
+		g.P("	o.mergeMessage(dstMsg, srcMsg, newPathTree(mask.GetPaths()))")
+		g.P("	return nil")
+		g.P("}")
+		g.P()
+		g.P("func (o MergeOptions) mergeMessage(dst, src ", protoreflectPackage.Ident("Message"), ", t pathTree) {")
+		g.P("	fields := dst.Descriptor().Fields()")
+		g.P("	for i := 0; i < fields.Len(); i++ {")
+		g.P("		fd := fields.Get(i)")
+		g.P("		sub, ok := t.field(fd)")
+		g.P("		switch {")
+		g.P("		case !ok:")
+		g.P("		case sub != nil:")
+		g.P("			if dst.Has(fd) || src.Has(fd) {")
+		g.P("				o.mergeMessage(dst.Mutable(fd).Message(), src.Get(fd).Message(), sub)")
+		g.P("			}")
+		g.P("		case fd.IsList() || fd.IsMap():")
+		g.P("			if o.ReplaceRepeated {")
+		g.P("				dst.Clear(fd)")
+		g.P("			}")
+		g.P("			if src.Has(fd) {")
+		g.P("				mergeField(dst, fd, src.Get(fd))")
+		g.P("			}")
+		g.P("		case fd.Message() != nil:")
+		g.P("			if o.ReplaceMessages || !src.Has(fd) {")
+		g.P("				dst.Clear(fd)")
+		g.P("			}")
+		g.P("			if src.Has(fd) {")
+		g.P("				mergeField(dst, fd, src.Get(fd))")
+		g.P("			}")
+		g.P("		default:")
+		g.P("			if src.Has(fd) {")
+		g.P("				mergeField(dst, fd, src.Get(fd))")
+		g.P("			} else {")
+		g.P("				dst.Clear(fd)")
+		g.P("			}")
+		g.P("		}")
+		g.P("	}")
+		g.P("}")
+		g.P()
+		g.P("// mergeField merges a copy of the value v of the field fd into dst.")
+		g.P("func mergeField(dst ", protoreflectPackage.Ident("Message"), ", fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ") {")
+		g.P("	switch {")
+		g.P("	case fd.IsList():")
+		g.P("		dstList, srcList := dst.Mutable(fd).List(), v.List()")
+		g.P("		for i := 0; i < srcList.Len(); i++ {")
+		g.P("			dstList.Append(copyValue(fd, srcList.Get(i)))")
+		g.P("		}")
+		g.P("	case fd.IsMap():")
+		g.P("		dstMap := dst.Mutable(fd).Map()")
+		g.P("		v.Map().Range(func(k ", protoreflectPackage.Ident("MapKey"), ", v ", protoreflectPackage.Ident("Value"), ") bool {")
+		g.P("			dstMap.Set(k, copyValue(fd.MapValue(), v))")
+		g.P("			return true")
+		g.P("		})")
+		g.P("	case fd.Message() != nil:")
+		g.P("		", protoPackage.Ident("Merge"), "(dst.Mutable(fd).Message().Interface(), v.Message().Interface())")
+		g.P("	default:")
+		g.P("		dst.Set(fd, copyValue(fd, v))")
+		g.P("	}")
+		g.P("}")
+		g.P()
+		g.P("// copyValue returns a copy of the singular value v of the field fd.")
+		g.P("func copyValue(fd ", protoreflectPackage.Ident("FieldDescriptor"), ", v ", protoreflectPackage.Ident("Value"), ") ", protoreflectPackage.Ident("Value"), " {")
+		g.P("	switch {")
+		g.P("	case fd.Message() != nil:")
+		g.P("		return ", protoreflectPackage.Ident("ValueOfMessage"), "(", protoPackage.Ident("Clone"), "(v.Message().Interface()).ProtoReflect())")
+		g.P("	case fd.Kind() == ", protoreflectPackage.Ident("BytesKind"), ":")
+		g.P("		return ", protoreflectPackage.Ident("ValueOfBytes"), "(append([]byte(nil), v.Bytes()...))")
+		g.P("	}")
+		g.P("	return v")
+		g.P("}")
+		g.P()
+		g.P("// checkPaths reports an error for the first path which is not valid")
+		g.P("// for the message type of m.")
+		g.P("func checkPaths(m ", protoPackage.Ident("Message"), ", paths []string) error {")
+		g.P("	if n := numValidPaths(m, paths); n < len(paths) {")
+		g.P("		name := m.ProtoReflect().Descriptor().FullName()")
+		g.P("		return ", protoimplPackage.Ident("X"), ".NewError(\"invalid path %q for message %q\", paths[n], name)")
+		g.P("	}")
+		g.P("	return nil")
+		g.P("}")
+		g.P()
+		// The path tree mirrors internal/fieldmask.Tree, used by the Mask option
+		// of proto.MarshalOptions. It is generated inline since generated code
+		// cannot import the internal packages of the runtime module.
+		g.P("// pathTree is a set of paths. Each key is the name of a field of a message,")
+		g.P("// and its value holds the paths within that field. A nil value selects the")
+		g.P("// field in its entirety.")
+		g.P("type pathTree map[string]pathTree")
+		g.P()
+		g.P("func newPathTree(paths []string) pathTree {")
+		g.P("	t := pathTree{}")
+		g.P("	for _, path := range paths {")
+		g.P("		node, name := t, \"\"")
+		g.P("		if !rangeFields(path, func(field string) bool {")
+		g.P("			if name != \"\" {")
+		g.P("				sub, ok := node[name]")
+		g.P("				if ok && sub == nil {")
+		g.P("					return false // the field is already selected in its entirety")
+		g.P("				}")
+		g.P("				if !ok {")
+		g.P("					sub = pathTree{}")
+		g.P("					node[name] = sub")
+		g.P("				}")
+		g.P("				node = sub")
+		g.P("			}")
+		g.P("			name = field")
+		g.P("			return true")
+		g.P("		}) {")
+		g.P("			continue")
+		g.P("		}")
+		g.P("		node[name] = nil")
+		g.P("	}")
+		g.P("	return t")
+		g.P("}")
+		g.P()
+		g.P("// field returns the paths within the field fd, and reports whether any")
+		g.P("// part of the field is selected. The name of a group is its message name.")
+		g.P("func (t pathTree) field(fd ", protoreflectPackage.Ident("FieldDescriptor"), ") (pathTree, bool) {")
+		g.P("	if fd.IsExtension() {")
+		g.P("		return nil, false")
+		g.P("	}")
+		g.P("	name := string(fd.Name())")
+		g.P("	if fd.Kind() == ", protoreflectPackage.Ident("GroupKind"), " {")
+		g.P("		name = string(fd.Message().Name())")
+		g.P("	}")
+		g.P("	sub, ok := t[name]")
+		g.P("	return sub, ok")
+		g.P("}")
+		g.P()
+
 	case genid.BoolValue_message_fullname,
 		genid.Int32Value_message_fullname,
 		genid.Int64Value_message_fullname,
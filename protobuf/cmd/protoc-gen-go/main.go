@@ -38,6 +38,7 @@ func main() {
 		flags   flag.FlagSet
 		plugins = flags.String("plugins", "", "deprecated option")
 	)
+	flags.Var(&gengo.DefaultAPILevel, "api_level", "API of generated message types (supported values: open, hybrid, opaque)")
 	protogen.Options{
 		ParamFunc: flags.Set,
 	}.Run(func(gen *protogen.Plugin) error {
@@ -51,6 +52,8 @@ func main() {
 			}
 		}
 		gen.SupportedFeatures = gengo.SupportedFeatures
+		gen.SupportedEditionsMinimum = gengo.SupportedEditionsMinimum
+		gen.SupportedEditionsMaximum = gengo.SupportedEditionsMaximum
 		return nil
 	})
 }
@@ -14,6 +14,7 @@ import (
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/extensions/extra"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/extensions/proto3"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/fieldnames"
+	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/hybrid"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/import_public"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/import_public/sub"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/import_public/sub2"
@@ -24,6 +25,7 @@ import (
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/imports/test_b_1"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/issue780_oneof_conflict"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/nopackage"
+	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/opaque"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/proto2"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/proto3"
 	_ "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/protoeditions"
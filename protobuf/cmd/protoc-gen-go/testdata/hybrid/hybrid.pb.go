@@ -0,0 +1,614 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: cmd/protoc-gen-go/testdata/hybrid/hybrid.proto
+
+package hybrid
+
+import (
+	protoreflect "github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	protoimpl "github.com/golang/protobuf/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+type Enum int32
+
+const (
+	Enum_ENUM_ZERO Enum = 0
+	Enum_ENUM_ONE  Enum = 1
+)
+
+// Enum value maps for Enum.
+var (
+	Enum_name = map[int32]string{
+		0: "ENUM_ZERO",
+		1: "ENUM_ONE",
+	}
+	Enum_value = map[string]int32{
+		"ENUM_ZERO": 0,
+		"ENUM_ONE":  1,
+	}
+)
+
+func (x Enum) Enum() *Enum {
+	p := new(Enum)
+	*p = x
+	return p
+}
+
+func (x Enum) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Enum) Descriptor() protoreflect.EnumDescriptor {
+	return file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_enumTypes[0].Descriptor()
+}
+
+func (Enum) Type() protoreflect.EnumType {
+	return &file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_enumTypes[0]
+}
+
+func (x Enum) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Enum.Descriptor instead.
+func (Enum) EnumDescriptor() ([]byte, []int) {
+	return file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescGZIP(), []int{0}
+}
+
+type Message struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name     string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count    *int32              `protobuf:"varint,2,opt,name=count,proto3,oneof" json:"count,omitempty"`
+	Data     []byte              `protobuf:"bytes,3,opt,name=data,proto3,oneof" json:"data,omitempty"`
+	Kind     Enum                `protobuf:"varint,4,opt,name=kind,proto3,enum=goproto.protoc.hybrid.Enum" json:"kind,omitempty"`
+	Child    *Message            `protobuf:"bytes,5,opt,name=child,proto3" json:"child,omitempty"`
+	Tags     []string            `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Children map[string]*Message `protobuf:"bytes,7,rep,name=children,proto3" json:"children,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Types that are assignable to Choice:
+	//	*Message_Text
+	//	*Message_Blob
+	//	*Message_Nested
+	//	*Message_Value
+	Choice  isMessage_Choice `protobuf_oneof:"choice"`
+	Payload *Message         `protobuf:"bytes,12,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Message) GetCount() int32 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *Message) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Message) GetKind() Enum {
+	if x != nil {
+		return x.Kind
+	}
+	return Enum_ENUM_ZERO
+}
+
+func (x *Message) GetChild() *Message {
+	if x != nil {
+		return x.Child
+	}
+	return nil
+}
+
+func (x *Message) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Message) GetChildren() map[string]*Message {
+	if x != nil {
+		return x.Children
+	}
+	return nil
+}
+
+func (m *Message) GetChoice() isMessage_Choice {
+	if m != nil {
+		return m.Choice
+	}
+	return nil
+}
+
+func (x *Message) GetText() string {
+	if x, ok := x.GetChoice().(*Message_Text); ok {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Message) GetBlob() []byte {
+	if x, ok := x.GetChoice().(*Message_Blob); ok {
+		return x.Blob
+	}
+	return nil
+}
+
+func (x *Message) GetNested() *Message {
+	if x, ok := x.GetChoice().(*Message_Nested); ok {
+		return x.Nested
+	}
+	return nil
+}
+
+func (x *Message) GetValue() Enum {
+	if x, ok := x.GetChoice().(*Message_Value); ok {
+		return x.Value
+	}
+	return Enum_ENUM_ZERO
+}
+
+func (x *Message) GetPayload() *Message {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Message) SetName(v string) {
+	x.Name = v
+}
+
+func (x *Message) SetCount(v int32) {
+	x.Count = &v
+}
+
+func (x *Message) HasCount() bool {
+	if x == nil {
+		return false
+	}
+	return x.Count != nil
+}
+
+func (x *Message) ClearCount() {
+	x.Count = nil
+}
+
+func (x *Message) SetData(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.Data = v
+}
+
+func (x *Message) HasData() bool {
+	if x == nil {
+		return false
+	}
+	return x.Data != nil
+}
+
+func (x *Message) ClearData() {
+	x.Data = nil
+}
+
+func (x *Message) SetKind(v Enum) {
+	x.Kind = v
+}
+
+func (x *Message) SetChild(v *Message) {
+	x.Child = v
+}
+
+func (x *Message) HasChild() bool {
+	if x == nil {
+		return false
+	}
+	return x.Child != nil
+}
+
+func (x *Message) ClearChild() {
+	x.Child = nil
+}
+
+func (x *Message) SetTags(v []string) {
+	x.Tags = v
+}
+
+func (x *Message) SetChildren(v map[string]*Message) {
+	x.Children = v
+}
+
+func (x *Message) HasChoice() bool {
+	if x == nil {
+		return false
+	}
+	return x.Choice != nil
+}
+
+func (x *Message) ClearChoice() {
+	x.Choice = nil
+}
+
+// case_Message_Choice is the number of the field that is set in the choice oneof
+// of Message, or zero if none is set.
+type case_Message_Choice protoreflect.FieldNumber
+
+const (
+	Message_Choice_not_set_case case_Message_Choice = 0
+	Message_Text_case           case_Message_Choice = 8
+	Message_Blob_case           case_Message_Choice = 9
+	Message_Nested_case         case_Message_Choice = 10
+	Message_Value_case          case_Message_Choice = 11
+)
+
+func (x *Message) WhichChoice() case_Message_Choice {
+	if x == nil {
+		return Message_Choice_not_set_case
+	}
+	switch x.Choice.(type) {
+	case *Message_Text:
+		return Message_Text_case
+	case *Message_Blob:
+		return Message_Blob_case
+	case *Message_Nested:
+		return Message_Nested_case
+	case *Message_Value:
+		return Message_Value_case
+	default:
+		return Message_Choice_not_set_case
+	}
+}
+
+func (x *Message) SetText(v string) {
+	x.Choice = &Message_Text{v}
+}
+
+func (x *Message) HasText() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.Choice.(*Message_Text)
+	return ok
+}
+
+func (x *Message) ClearText() {
+	if _, ok := x.Choice.(*Message_Text); ok {
+		x.Choice = nil
+	}
+}
+
+func (x *Message) SetBlob(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.Choice = &Message_Blob{v}
+}
+
+func (x *Message) HasBlob() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.Choice.(*Message_Blob)
+	return ok
+}
+
+func (x *Message) ClearBlob() {
+	if _, ok := x.Choice.(*Message_Blob); ok {
+		x.Choice = nil
+	}
+}
+
+func (x *Message) SetNested(v *Message) {
+	if v == nil {
+		x.Choice = nil
+		return
+	}
+	x.Choice = &Message_Nested{v}
+}
+
+func (x *Message) HasNested() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.Choice.(*Message_Nested)
+	return ok
+}
+
+func (x *Message) ClearNested() {
+	if _, ok := x.Choice.(*Message_Nested); ok {
+		x.Choice = nil
+	}
+}
+
+func (x *Message) SetValue(v Enum) {
+	x.Choice = &Message_Value{v}
+}
+
+func (x *Message) HasValue() bool {
+	if x == nil {
+		return false
+	}
+	_, ok := x.Choice.(*Message_Value)
+	return ok
+}
+
+func (x *Message) ClearValue() {
+	if _, ok := x.Choice.(*Message_Value); ok {
+		x.Choice = nil
+	}
+}
+
+func (x *Message) SetPayload(v *Message) {
+	x.Payload = v
+}
+
+func (x *Message) HasPayload() bool {
+	if x == nil {
+		return false
+	}
+	return x.Payload != nil
+}
+
+func (x *Message) ClearPayload() {
+	x.Payload = nil
+}
+
+type Message_builder struct {
+	_ [0]func() // Prohibits unkeyed literals.
+
+	Name     string
+	Count    *int32
+	Data     []byte
+	Kind     Enum
+	Child    *Message
+	Tags     []string
+	Children map[string]*Message
+	// Fields of oneof choice:
+	Text   *string
+	Blob   []byte
+	Nested *Message
+	Value  *Enum
+	// -- end of choice
+	Payload *Message
+}
+
+func (b0 Message_builder) Build() *Message {
+	m0 := &Message{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.Name = b.Name
+	x.Count = b.Count
+	x.Data = b.Data
+	x.Kind = b.Kind
+	x.Child = b.Child
+	x.Tags = b.Tags
+	x.Children = b.Children
+	if b.Text != nil {
+		x.Choice = &Message_Text{*b.Text}
+	}
+	if b.Blob != nil {
+		x.Choice = &Message_Blob{b.Blob}
+	}
+	if b.Nested != nil {
+		x.Choice = &Message_Nested{b.Nested}
+	}
+	if b.Value != nil {
+		x.Choice = &Message_Value{*b.Value}
+	}
+	x.Payload = b.Payload
+	return m0
+}
+
+type isMessage_Choice interface {
+	isMessage_Choice()
+}
+
+type Message_Text struct {
+	Text string `protobuf:"bytes,8,opt,name=text,proto3,oneof"`
+}
+
+type Message_Blob struct {
+	Blob []byte `protobuf:"bytes,9,opt,name=blob,proto3,oneof"`
+}
+
+type Message_Nested struct {
+	Nested *Message `protobuf:"bytes,10,opt,name=nested,proto3,oneof"`
+}
+
+type Message_Value struct {
+	Value Enum `protobuf:"varint,11,opt,name=value,proto3,enum=goproto.protoc.hybrid.Enum,oneof"`
+}
+
+func (*Message_Text) isMessage_Choice() {}
+
+func (*Message_Blob) isMessage_Choice() {}
+
+func (*Message_Nested) isMessage_Choice() {}
+
+func (*Message_Value) isMessage_Choice() {}
+
+var File_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto protoreflect.FileDescriptor
+
+var file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDesc = []byte{
+	0x0a, 0x2e, 0x63, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e,
+	0x2d, 0x67, 0x6f, 0x2f, 0x74, 0x65, 0x73, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2f, 0x68, 0x79, 0x62,
+	0x72, 0x69, 0x64, 0x2f, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x15, 0x67, 0x6f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x2e, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64, 0x22, 0xe9, 0x04, 0x0a, 0x07, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x19, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x88,
+	0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c,
+	0x48, 0x02, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79, 0x62, 0x72, 0x69,
+	0x64, 0x2e, 0x45, 0x6e, 0x75, 0x6d, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x34, 0x0a, 0x05,
+	0x63, 0x68, 0x69, 0x6c, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67, 0x6f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79, 0x62,
+	0x72, 0x69, 0x64, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x63, 0x68, 0x69,
+	0x6c, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x48, 0x0a, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72,
+	0x65, 0x6e, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x67, 0x6f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64,
+	0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x2e, 0x43, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65,
+	0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x72, 0x65, 0x6e,
+	0x12, 0x14, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00,
+	0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14, 0x0a, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0c, 0x48, 0x00, 0x52, 0x04, 0x62, 0x6c, 0x6f, 0x62, 0x12, 0x38, 0x0a, 0x06,
+	0x6e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67,
+	0x6f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79,
+	0x62, 0x72, 0x69, 0x64, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x00, 0x52, 0x06,
+	0x6e, 0x65, 0x73, 0x74, 0x65, 0x64, 0x12, 0x33, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x67, 0x6f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79, 0x62, 0x72, 0x69, 0x64, 0x2e, 0x45, 0x6e,
+	0x75, 0x6d, 0x48, 0x00, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x3c, 0x0a, 0x07, 0x70,
+	0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67,
+	0x6f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79,
+	0x62, 0x72, 0x69, 0x64, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x02, 0x28, 0x01,
+	0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x1a, 0x5b, 0x0a, 0x0d, 0x43, 0x68, 0x69,
+	0x6c, 0x64, 0x72, 0x65, 0x6e, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x34, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x67, 0x6f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2e, 0x68, 0x79, 0x62,
+	0x72, 0x69, 0x64, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x08, 0x0a, 0x06, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x64,
+	0x61, 0x74, 0x61, 0x2a, 0x23, 0x0a, 0x04, 0x45, 0x6e, 0x75, 0x6d, 0x12, 0x0d, 0x0a, 0x09, 0x45,
+	0x4e, 0x55, 0x4d, 0x5f, 0x5a, 0x45, 0x52, 0x4f, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x45, 0x4e,
+	0x55, 0x4d, 0x5f, 0x4f, 0x4e, 0x45, 0x10, 0x01, 0x42, 0x47, 0x5a, 0x45, 0x67, 0x69, 0x74, 0x68,
+	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x63, 0x6d, 0x64, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67,
+	0x6f, 0x2f, 0x74, 0x65, 0x73, 0x74, 0x64, 0x61, 0x74, 0x61, 0x2f, 0x68, 0x79, 0x62, 0x72, 0x69,
+	0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescOnce sync.Once
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescData = file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDesc
+)
+
+func file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescGZIP() []byte {
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescOnce.Do(func() {
+		file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescData = protoimpl.X.CompressGZIP(file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescData)
+	})
+	return file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDescData
+}
+
+var file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_goTypes = []interface{}{
+	(Enum)(0),       // 0: goproto.protoc.hybrid.Enum
+	(*Message)(nil), // 1: goproto.protoc.hybrid.Message
+	nil,             // 2: goproto.protoc.hybrid.Message.ChildrenEntry
+}
+var file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_depIdxs = []int32{
+	0, // 0: goproto.protoc.hybrid.Message.kind:type_name -> goproto.protoc.hybrid.Enum
+	1, // 1: goproto.protoc.hybrid.Message.child:type_name -> goproto.protoc.hybrid.Message
+	2, // 2: goproto.protoc.hybrid.Message.children:type_name -> goproto.protoc.hybrid.Message.ChildrenEntry
+	1, // 3: goproto.protoc.hybrid.Message.nested:type_name -> goproto.protoc.hybrid.Message
+	0, // 4: goproto.protoc.hybrid.Message.value:type_name -> goproto.protoc.hybrid.Enum
+	1, // 5: goproto.protoc.hybrid.Message.payload:type_name -> goproto.protoc.hybrid.Message
+	1, // 6: goproto.protoc.hybrid.Message.ChildrenEntry.value:type_name -> goproto.protoc.hybrid.Message
+	7, // [7:7] is the sub-list for method output_type
+	7, // [7:7] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_init() }
+func file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_init() {
+	if File_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Message); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_msgTypes[0].OneofWrappers = []interface{}{
+		(*Message_Text)(nil),
+		(*Message_Blob)(nil),
+		(*Message_Nested)(nil),
+		(*Message_Value)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_goTypes,
+		DependencyIndexes: file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_depIdxs,
+		EnumInfos:         file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_enumTypes,
+		MessageInfos:      file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_msgTypes,
+	}.Build()
+	File_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto = out.File
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_rawDesc = nil
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_goTypes = nil
+	file_cmd_protoc_gen_go_testdata_hybrid_hybrid_proto_depIdxs = nil
+}
@@ -108,6 +108,12 @@ type Plugin struct {
 	// google.protobuf.CodeGeneratorResponse.supported_features for details.
 	SupportedFeatures uint64
 
+	// SupportedEditionsMinimum and SupportedEditionsMaximum are the oldest
+	// and newest editions supported by this generator plugin. They are only
+	// reported if SupportedFeatures includes FEATURE_SUPPORTS_EDITIONS.
+	SupportedEditionsMinimum descriptorpb.Edition
+	SupportedEditionsMaximum descriptorpb.Edition
+
 	fileReg        *protoregistry.Files
 	enumsByName    map[protoreflect.FullName]*Enum
 	messagesByName map[protoreflect.FullName]*Message
@@ -396,6 +402,10 @@ func (gen *Plugin) Response() *pluginpb.CodeGeneratorResponse {
 	if gen.SupportedFeatures > 0 {
 		resp.SupportedFeatures = proto.Uint64(gen.SupportedFeatures)
 	}
+	if gen.SupportedFeatures&uint64(pluginpb.CodeGeneratorResponse_FEATURE_SUPPORTS_EDITIONS) != 0 {
+		resp.MinimumEdition = proto.Int32(int32(gen.SupportedEditionsMinimum))
+		resp.MaximumEdition = proto.Int32(int32(gen.SupportedEditionsMaximum))
+	}
 	return resp
 }
 
@@ -0,0 +1,92 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson
+
+import (
+	"github.com/golang/protobuf/protobuf/internal/encoding/json"
+	"github.com/golang/protobuf/protobuf/internal/errors"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+)
+
+// typeMarshaler returns a marshal function if the message type has
+// a custom or well-known JSON encoding. It returns nil otherwise.
+func (e encoder) typeMarshaler(name protoreflect.FullName) marshalFunc {
+	if f, ok := e.opts.Marshalers[name]; ok && f != nil {
+		return func(e encoder, m protoreflect.Message) error {
+			b, err := f(m.Interface())
+			if err != nil {
+				return err
+			}
+			if err := e.copyJSONValue(json.NewDecoder(b)); err != nil {
+				return errors.New("%v: invalid JSON from custom marshaler: %v", name, err)
+			}
+			return nil
+		}
+	}
+	return wellKnownTypeMarshaler(name)
+}
+
+// copyJSONValue writes out the single JSON value read from d, so that it is
+// formatted as the rest of the output.
+func (e encoder) copyJSONValue(d *json.Decoder) error {
+	var n int
+	for ; ; n++ {
+		tok, err := d.Read()
+		if err != nil {
+			return err
+		}
+		switch tok.Kind() {
+		case json.EOF:
+			if n == 0 {
+				return errors.New("empty input")
+			}
+			return nil
+		case json.Null:
+			e.WriteNull()
+		case json.Bool:
+			e.WriteBool(tok.Bool())
+		case json.Number:
+			e.WriteNumber(tok.RawString())
+		case json.String:
+			if err := e.WriteString(tok.ParsedString()); err != nil {
+				return err
+			}
+		case json.Name:
+			if err := e.WriteName(tok.Name()); err != nil {
+				return err
+			}
+		case json.ObjectOpen:
+			e.StartObject()
+		case json.ObjectClose:
+			e.EndObject()
+		case json.ArrayOpen:
+			e.StartArray()
+		case json.ArrayClose:
+			e.EndArray()
+		}
+	}
+}
+
+// typeUnmarshaler returns an unmarshal function if the message type has
+// a custom or well-known JSON encoding. It returns nil otherwise.
+func (d decoder) typeUnmarshaler(name protoreflect.FullName) unmarshalFunc {
+	if f, ok := d.opts.Unmarshalers[name]; ok && f != nil {
+		return func(d decoder, m protoreflect.Message) error {
+			tok, err := d.Peek()
+			if err != nil {
+				return err
+			}
+			b, err := d.ReadValue()
+			if err != nil {
+				return err
+			}
+			if err := f(b, m.Interface()); err != nil {
+				return d.newError(tok.Pos(), "%v: %v", name, err)
+			}
+			return nil
+		}
+	}
+	return wellKnownTypeUnmarshaler(name)
+}
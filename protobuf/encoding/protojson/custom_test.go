@@ -0,0 +1,174 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/protobuf/encoding/protojson"
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+
+	pb3 "github.com/golang/protobuf/protobuf/internal/testprotos/textpb3"
+	"github.com/golang/protobuf/protobuf/types/known/anypb"
+	"github.com/golang/protobuf/protobuf/types/known/durationpb"
+)
+
+// Custom encodings for testing: pb3.Nested as a JSON string holding
+// its s_string field, and google.protobuf.Duration as a number of seconds.
+var (
+	customMarshalers = map[protoreflect.FullName]func(proto.Message) ([]byte, error){
+		"pb3.Nested": func(m proto.Message) ([]byte, error) {
+			return []byte(strconv.Quote(m.(*pb3.Nested).GetSString())), nil
+		},
+		"google.protobuf.Duration": func(m proto.Message) ([]byte, error) {
+			return []byte(fmt.Sprint(m.(*durationpb.Duration).AsDuration().Seconds())), nil
+		},
+	}
+	customUnmarshalers = map[protoreflect.FullName]func([]byte, proto.Message) error{
+		"pb3.Nested": func(b []byte, m proto.Message) error {
+			s, err := strconv.Unquote(string(b))
+			if err != nil {
+				return errors.New("want a JSON string")
+			}
+			m.(*pb3.Nested).SString = s
+			return nil
+		},
+		"google.protobuf.Duration": func(b []byte, m proto.Message) error {
+			f, err := strconv.ParseFloat(string(b), 64)
+			if err != nil {
+				return errors.New("want a number of seconds")
+			}
+			proto.Merge(m, durationpb.New(time.Duration(f*float64(time.Second))))
+			return nil
+		},
+	}
+)
+
+func TestCustomCodecs(t *testing.T) {
+	tests := []struct {
+		desc string
+		mo   protojson.MarshalOptions
+		m    proto.Message
+		want string
+	}{{
+		desc: "message field",
+		m:    &pb3.Nests{SNested: &pb3.Nested{SString: "12.34 USD"}},
+		want: `{"sNested":"12.34 USD"}`,
+	}, {
+		desc: "top-level message",
+		m:    &pb3.Nested{SString: "12.34 USD"},
+		want: `"12.34 USD"`,
+	}, {
+		desc: "map value",
+		m:    &pb3.Maps{StrToNested: map[string]*pb3.Nested{"a": {SString: "x"}}},
+		want: `{"strToNested":{"a":"x"}}`,
+	}, {
+		desc: "overridden well-known type",
+		m:    durationpb.New(1500 * time.Millisecond),
+		want: `1.5`,
+	}, {
+		desc: "Any",
+		m: func() proto.Message {
+			m, err := anypb.New(&pb3.Nested{SString: "12.34 USD"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return m
+		}(),
+		want: `{"@type":"type.googleapis.com/pb3.Nested","value":"12.34 USD"}`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			mo := tt.mo
+			mo.Marshalers = customMarshalers
+			b, err := mo.Marshal(tt.m)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			if got := strings.ReplaceAll(string(b), ", ", ","); got != tt.want {
+				t.Errorf("Marshal = %s, want %s", got, tt.want)
+			}
+
+			got := tt.m.ProtoReflect().New().Interface()
+			uo := protojson.UnmarshalOptions{Unmarshalers: customUnmarshalers}
+			if err := uo.Unmarshal(b, got); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if !proto.Equal(got, tt.m) {
+				t.Errorf("Unmarshal = %v, want %v", got, tt.m)
+			}
+		})
+	}
+}
+
+func TestCustomMarshalerFormat(t *testing.T) {
+	mo := protojson.MarshalOptions{
+		Indent: "  ",
+		Marshalers: map[protoreflect.FullName]func(proto.Message) ([]byte, error){
+			"pb3.Nested": func(m proto.Message) ([]byte, error) {
+				return []byte(`{"amount":  12.340, "currency":"USD", "tags":[]}`), nil
+			},
+		},
+	}
+	b, err := mo.Marshal(&pb3.Nests{SNested: &pb3.Nested{}})
+	if err != nil {
+		t.Fatalf("Marshal error: %v", err)
+	}
+	want := `{
+  "sNested": {
+    "amount": 12.340,
+    "currency": "USD",
+    "tags": []
+  }
+}`
+	if got := string(b); got != want {
+		t.Errorf("Marshal =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestCustomCodecErrors(t *testing.T) {
+	marshalErr := errors.New("marshaler error")
+	for _, tt := range []struct {
+		out     string
+		err     error
+		wantErr string
+	}{
+		{err: marshalErr, wantErr: "marshaler error"},
+		{out: "", wantErr: "invalid JSON from custom marshaler"},
+		{out: `{"a":`, wantErr: "invalid JSON from custom marshaler"},
+		{out: `1 2`, wantErr: "invalid JSON from custom marshaler"},
+	} {
+		mo := protojson.MarshalOptions{
+			Marshalers: map[protoreflect.FullName]func(proto.Message) ([]byte, error){
+				"pb3.Nested": func(proto.Message) ([]byte, error) { return []byte(tt.out), tt.err },
+			},
+		}
+		_, err := mo.Marshal(&pb3.Nests{SNested: &pb3.Nested{}})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Marshal with custom output %q, error %v: got error %v, want %q", tt.out, tt.err, err, tt.wantErr)
+		}
+	}
+
+	uo := protojson.UnmarshalOptions{Unmarshalers: customUnmarshalers}
+	for _, tt := range []struct {
+		in      string
+		wantErr string
+	}{
+		{in: "{\n\"sNested\": 12}", wantErr: "(line 2:12): pb3.Nested: want a JSON string"},
+		{in: `{"sNested": {"sString": "x"`, wantErr: "unexpected EOF"},
+		{in: `{"sNested": "x",}`, wantErr: "unexpected token }"},
+	} {
+		err := uo.Unmarshal([]byte(tt.in), &pb3.Nests{})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Unmarshal(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+		}
+	}
+}
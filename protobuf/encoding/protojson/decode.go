@@ -52,21 +52,61 @@ type UnmarshalOptions struct {
 	// RecursionLimit limits how deeply messages may be nested.
 	// If zero, a default limit is applied.
 	RecursionLimit int
+
+	// Lenient specifies which inputs outside of the JSON format to accept,
+	// such as those sent by legacy clients. Regardless of it, numbers are
+	// accepted as strings, fields may be named by their JSON or proto name,
+	// and null is accepted for any field, including repeated and map fields.
+	Lenient LenientFlags
+
+	// FieldNamer, if set, provides an additional name by which each
+	// non-extension field may be referred to, such as the name given by the
+	// MarshalOptions.FieldNamer that produced the input. It takes precedence
+	// over the JSON and proto field names, which are still accepted.
+	FieldNamer func(protoreflect.FieldDescriptor) string
+
+	// Unmarshalers provides custom JSON encodings for messages, keyed by
+	// the full name of the message type. Each function is given the JSON value
+	// for a message of that type and populates the message, which is empty.
+	// It takes precedence over the encoding of well-known types.
+	// A message with a custom encoding is read from the "value" field of
+	// a google.protobuf.Any, as well-known types are.
+	Unmarshalers map[protoreflect.FullName]func([]byte, proto.Message) error
 }
 
+// LenientFlags is a set of relaxations of the JSON format for UnmarshalOptions.
+type LenientFlags uint8
+
+const (
+	// LenientEnumCase accepts enum value names in any case,
+	// if no value has the exact name.
+	LenientEnumCase LenientFlags = 1 << iota
+
+	// LenientEnumNumberStrings accepts enum numbers given as strings,
+	// such as "1".
+	LenientEnumNumberStrings
+
+	// LenientDuplicateFields accepts a field or map key that appears more than
+	// once in an object. The last value wins; the earlier ones are discarded.
+	LenientDuplicateFields
+
+	// LenientAll accepts all of the above.
+	LenientAll = LenientEnumCase | LenientEnumNumberStrings | LenientDuplicateFields
+)
+
 // Unmarshal reads the given []byte and populates the given [proto.Message]
 // using options in the UnmarshalOptions object.
 // It will clear the message first before setting the fields.
 // If it returns an error, the given message may be partially set.
 // The provided message must be mutable (e.g., a non-nil pointer to a message).
 func (o UnmarshalOptions) Unmarshal(b []byte, m proto.Message) error {
-	return o.unmarshal(b, m)
+	return o.unmarshal(json.NewDecoder(b), m)
 }
 
 // unmarshal is a centralized function that all unmarshal operations go through.
 // For profiling purposes, avoid changing the name of this function or
 // introducing other code paths for unmarshal that do not go through this.
-func (o UnmarshalOptions) unmarshal(b []byte, m proto.Message) error {
+func (o UnmarshalOptions) unmarshal(jd *json.Decoder, m proto.Message) error {
 	proto.Reset(m)
 
 	if o.Resolver == nil {
@@ -76,7 +116,10 @@ func (o UnmarshalOptions) unmarshal(b []byte, m proto.Message) error {
 		o.RecursionLimit = protowire.DefaultRecursionLimit
 	}
 
-	dec := decoder{json.NewDecoder(b), o}
+	dec := decoder{jd, o, nil}
+	if o.FieldNamer != nil {
+		dec.names = make(map[protoreflect.FullName]map[string]protoreflect.FieldDescriptor)
+	}
 	if err := dec.unmarshalMessage(m.ProtoReflect(), false); err != nil {
 		return err
 	}
@@ -99,6 +142,25 @@ func (o UnmarshalOptions) unmarshal(b []byte, m proto.Message) error {
 type decoder struct {
 	*json.Decoder
 	opts UnmarshalOptions
+
+	// names holds the field names given by opts.FieldNamer for each message
+	// unmarshaled so far.
+	names map[protoreflect.FullName]map[string]protoreflect.FieldDescriptor
+}
+
+// fieldByNamer returns the field of md named name by opts.FieldNamer.
+func (d decoder) fieldByNamer(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	names, ok := d.names[md.FullName()]
+	if !ok {
+		fds := md.Fields()
+		names = make(map[string]protoreflect.FieldDescriptor, fds.Len())
+		for i := fds.Len() - 1; i >= 0; i-- {
+			fd := fds.Get(i)
+			names[d.opts.FieldNamer(fd)] = fd
+		}
+		d.names[md.FullName()] = names
+	}
+	return names[name]
 }
 
 // newError returns an error object with position info.
@@ -126,7 +188,7 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 	if d.opts.RecursionLimit < 0 {
 		return errors.New("exceeded max recursion depth")
 	}
-	if unmarshal := wellKnownTypeUnmarshaler(m.Descriptor().FullName()); unmarshal != nil {
+	if unmarshal := d.typeUnmarshaler(m.Descriptor().FullName()); unmarshal != nil {
 		return unmarshal(d, m)
 	}
 
@@ -186,8 +248,14 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 				}
 			}
 		} else {
-			// The name can either be the JSON name or the proto field name.
-			fd = fieldDescs.ByJSONName(name)
+			// The name can either be the JSON name or the proto field name,
+			// or the name given by the FieldNamer.
+			if d.opts.FieldNamer != nil {
+				fd = d.fieldByNamer(messageDesc, name)
+			}
+			if fd == nil {
+				fd = fieldDescs.ByJSONName(name)
+			}
 			if fd == nil {
 				fd = fieldDescs.ByTextName(name)
 			}
@@ -209,10 +277,14 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 			return d.newError(tok.Pos(), "unknown field %v", tok.RawString())
 		}
 
-		// Do not allow duplicate fields.
+		// Do not allow duplicate fields, unless the last value wins.
 		num := uint64(fd.Number())
-		if seenNums.Has(num) {
-			return d.newError(tok.Pos(), "duplicate field %v", tok.RawString())
+		dup := seenNums.Has(num)
+		if dup {
+			if d.opts.Lenient&LenientDuplicateFields == 0 {
+				return d.newError(tok.Pos(), "duplicate field %v", tok.RawString())
+			}
+			m.Clear(fd)
 		}
 		seenNums.Set(num)
 
@@ -238,7 +310,7 @@ func (d decoder) unmarshalMessage(m protoreflect.Message, skipTypeURL bool) erro
 			// If field is a oneof, check if it has already been set.
 			if od := fd.ContainingOneof(); od != nil {
 				idx := uint64(od.Index())
-				if seenOneofs.Has(idx) {
+				if seenOneofs.Has(idx) && !dup {
 					return d.newError(tok.Pos(), "error parsing %s, oneof %v is already set", tok.RawString(), od.FullName())
 				}
 				seenOneofs.Set(idx)
@@ -343,7 +415,7 @@ func (d decoder) unmarshalScalar(fd protoreflect.FieldDescriptor) (protoreflect.
 		}
 
 	case protoreflect.EnumKind:
-		if v, ok := unmarshalEnum(tok, fd, d.opts.DiscardUnknown); ok {
+		if v, ok := unmarshalEnum(tok, fd, d.opts.DiscardUnknown, d.opts.Lenient); ok {
 			return v, nil
 		}
 
@@ -488,14 +560,27 @@ func unmarshalBytes(tok json.Token) (protoreflect.Value, bool) {
 	return protoreflect.ValueOfBytes(b), true
 }
 
-func unmarshalEnum(tok json.Token, fd protoreflect.FieldDescriptor, discardUnknown bool) (protoreflect.Value, bool) {
+func unmarshalEnum(tok json.Token, fd protoreflect.FieldDescriptor, discardUnknown bool, lenient LenientFlags) (protoreflect.Value, bool) {
 	switch tok.Kind() {
 	case json.String:
 		// Lookup EnumNumber based on name.
 		s := tok.ParsedString()
-		if enumVal := fd.Enum().Values().ByName(protoreflect.Name(s)); enumVal != nil {
+		values := fd.Enum().Values()
+		if enumVal := values.ByName(protoreflect.Name(s)); enumVal != nil {
 			return protoreflect.ValueOfEnum(enumVal.Number()), true
 		}
+		if lenient&LenientEnumCase != 0 {
+			for i := 0; i < values.Len(); i++ {
+				if enumVal := values.Get(i); strings.EqualFold(string(enumVal.Name()), s) {
+					return protoreflect.ValueOfEnum(enumVal.Number()), true
+				}
+			}
+		}
+		if lenient&LenientEnumNumberStrings != 0 {
+			if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+				return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), true
+			}
+		}
 		if discardUnknown {
 			return protoreflect.Value{}, true
 		}
@@ -620,7 +705,10 @@ Loop:
 
 		// Check for duplicate field name.
 		if mmap.Has(pkey) {
-			return d.newError(tok.Pos(), "duplicate map key %v", tok.RawString())
+			if d.opts.Lenient&LenientDuplicateFields == 0 {
+				return d.newError(tok.Pos(), "duplicate map key %v", tok.RawString())
+			}
+			mmap.Clear(pkey)
 		}
 
 		// Read and unmarshal field value.
@@ -0,0 +1,241 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/golang/protobuf/protobuf/internal/encoding/json"
+	"github.com/golang/protobuf/protobuf/internal/errors"
+	"github.com/golang/protobuf/protobuf/proto"
+)
+
+// Decoder reads a sequence of messages in JSON format from an input stream.
+//
+// If the input starts with '[', the messages are the elements of that
+// JSON array. Otherwise, they are JSON values separated by whitespace,
+// as in newline-delimited JSON (NDJSON). Only one message is held in
+// memory at a time. Errors report the line and column of the input
+// at which they occur.
+//
+// Messages whose JSON representation is an array, such as
+// google.protobuf.ListValue, cannot be read from newline-delimited JSON,
+// since an input starting with '[' is read as an array of messages.
+type Decoder struct {
+	opts  UnmarshalOptions
+	r     *bufio.Reader
+	state streamState
+	err   error
+
+	// line and column are the position of the next byte of r.
+	line, column int
+
+	// buf holds the value of the message being decoded.
+	buf []byte
+}
+
+type streamState uint8
+
+const (
+	streamStart     streamState = iota // before the first value
+	streamValues                       // values are separated by whitespace
+	streamArray                        // before the first element of an array
+	streamArrayNext                    // after an element of an array
+	streamDone                         // after the end of an array
+)
+
+// NewDecoder returns a Decoder reading messages from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return UnmarshalOptions{}.NewDecoder(r)
+}
+
+// NewDecoder returns a Decoder reading messages from r
+// using options in the UnmarshalOptions object.
+func (o UnmarshalOptions) NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		opts:   o,
+		r:      bufio.NewReader(r),
+		line:   1,
+		column: 1,
+	}
+}
+
+// Decode reads the next message of the input into m, as Unmarshal does.
+// It returns io.EOF if there are no more messages.
+//
+// An error in the syntax of the sequence, or in reading the input, is
+// returned by all later calls. Other errors, such as an unknown field,
+// only concern the current message, and the next call to Decode reads
+// the following message.
+func (d *Decoder) Decode(m proto.Message) error {
+	if d.err != nil {
+		return d.err
+	}
+	line, column, err := d.next()
+	if err != nil {
+		d.err = err
+		return err
+	}
+	return d.opts.unmarshal(json.NewDecoderAt(d.buf, line, column), m)
+}
+
+// next reads the next value of the sequence into d.buf,
+// and returns its position in the input.
+func (d *Decoder) next() (line, column int, err error) {
+	c, err := d.skipSpace()
+	if err != nil && err != io.EOF {
+		return 0, 0, err
+	}
+	eof := err == io.EOF
+	switch d.state {
+	case streamStart:
+		if !eof && c == '[' {
+			d.readByte()
+			d.state = streamArray
+			return d.next()
+		}
+		d.state = streamValues
+		if eof {
+			return 0, 0, io.EOF
+		}
+	case streamValues:
+		if eof {
+			return 0, 0, io.EOF
+		}
+	case streamArray, streamArrayNext:
+		if eof {
+			return 0, 0, json.ErrUnexpectedEOF
+		}
+		if c == ']' {
+			d.readByte()
+			d.state = streamDone
+			return d.next()
+		}
+		if d.state == streamArrayNext {
+			if c != ',' {
+				return 0, 0, d.syntaxError(`unexpected character %q, want "," or "]"`, c)
+			}
+			d.readByte()
+			if c, err = d.skipSpace(); err == io.EOF {
+				return 0, 0, json.ErrUnexpectedEOF
+			} else if err != nil {
+				return 0, 0, err
+			}
+		}
+		d.state = streamArrayNext
+	case streamDone:
+		if eof {
+			return 0, 0, io.EOF
+		}
+		return 0, 0, d.syntaxError("unexpected character %q after the end of the array", c)
+	}
+	line, column = d.line, d.column
+	return line, column, d.readValue()
+}
+
+// readValue reads a JSON value into d.buf. It only checks that strings,
+// objects and arrays are terminated; the value is parsed by Decode.
+func (d *Decoder) readValue() error {
+	d.buf = d.buf[:0]
+	var depth int
+	var inString, escaped bool
+	for {
+		c, err := d.peekByte()
+		if err == io.EOF {
+			if depth > 0 || inString {
+				return json.ErrUnexpectedEOF
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !inString && depth == 0 && len(d.buf) > 0 && isDelim(c) {
+			return nil // end of a literal, such as a number
+		}
+		switch {
+		case inString:
+		case c == '}' || c == ']':
+			if depth == 0 {
+				return d.syntaxError("unexpected character %q", c)
+			}
+		case depth == 0 && (c == ',' || c == ':'):
+			return d.syntaxError("unexpected character %q", c)
+		}
+		d.readByte()
+		d.buf = append(d.buf, c)
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+			if !inString && depth == 0 {
+				return nil
+			}
+		case inString:
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// isDelim reports whether c ends a JSON literal.
+func isDelim(c byte) bool {
+	switch c {
+	case ' ', '\t', '\r', '\n', ',', ':', '{', '}', '[', ']', '"':
+		return true
+	}
+	return false
+}
+
+// skipSpace skips whitespace, and returns the following byte.
+func (d *Decoder) skipSpace() (byte, error) {
+	for {
+		c, err := d.peekByte()
+		if err != nil {
+			return 0, err
+		}
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			d.readByte()
+		default:
+			return c, nil
+		}
+	}
+}
+
+func (d *Decoder) peekByte() (byte, error) {
+	b, err := d.r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// readByte consumes the byte returned by peekByte.
+func (d *Decoder) readByte() {
+	c, _ := d.r.ReadByte()
+	switch {
+	case c == '\n':
+		d.line++
+		d.column = 1
+	case c&0xc0 != 0x80: // not a continuation byte of a UTF-8 sequence
+		d.column++
+	}
+}
+
+// syntaxError returns a syntax error at the position of the next byte.
+func (d *Decoder) syntaxError(f string, x ...interface{}) error {
+	e := errors.New(f, x...)
+	return errors.New("syntax error (line %d:%d): %v", d.line, d.column, e)
+}
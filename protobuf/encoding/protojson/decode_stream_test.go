@@ -0,0 +1,172 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/golang/protobuf/protobuf/encoding/protojson"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	pb3 "github.com/golang/protobuf/protobuf/internal/testprotos/textpb3"
+	"github.com/golang/protobuf/protobuf/types/known/wrapperspb"
+)
+
+func TestDecoder(t *testing.T) {
+	want := []*pb3.Scalars{
+		{SInt32: 1},
+		{SString: `a "}]` + "\n" + `string`},
+		{},
+		{SBool: true, SInt64: 2},
+	}
+	tests := []struct {
+		desc  string
+		input string
+	}{{
+		desc:  "NDJSON",
+		input: `{"sInt32":1}` + "\n" + `{"sString":"a \"}]\nstring"}` + "\n{}\n" + `{"sBool":true,"sInt64":"2"}` + "\n",
+	}, {
+		desc:  "whitespace separated",
+		input: ` {"sInt32":1}{"sString":"a \"}]\nstring"}  {  }` + "\r\n\t" + `{"sBool" : true, "sInt64" : 2}`,
+	}, {
+		desc:  "array",
+		input: "[\n" + `  {"sInt32":1},` + "\n" + `  {"sString":"a \"}]\nstring"},{},` + "\n" + `  {"sBool":true,"sInt64":"2"}` + "\n]\n",
+	}}
+	for _, test := range tests {
+		for _, r := range []io.Reader{
+			strings.NewReader(test.input),
+			iotest.OneByteReader(strings.NewReader(test.input)),
+		} {
+			dec := protojson.NewDecoder(r)
+			for i := 0; ; i++ {
+				got := &pb3.Scalars{}
+				err := dec.Decode(got)
+				if err == io.EOF {
+					if i != len(want) {
+						t.Errorf("%s: decoded %v messages, want %v", test.desc, i, len(want))
+					}
+					break
+				}
+				if err != nil {
+					t.Errorf("%s: Decode error: %v", test.desc, err)
+					break
+				}
+				if i >= len(want) || !proto.Equal(got, want[i]) {
+					t.Errorf("%s: message %v = %v", test.desc, i, got)
+				}
+			}
+		}
+	}
+}
+
+func TestDecoderScalars(t *testing.T) {
+	for _, input := range []string{"1\n2\n\"3\"\n", "[1, 2,\"3\"]"} {
+		dec := protojson.NewDecoder(strings.NewReader(input))
+		var got []int64
+		for {
+			m := &wrapperspb.Int64Value{}
+			err := dec.Decode(m)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Decode(%q) error: %v", input, err)
+			}
+			got = append(got, m.GetValue())
+		}
+		if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Errorf("Decode(%q) = %v, want [1 2 3]", input, got)
+		}
+	}
+}
+
+func TestDecoderEmpty(t *testing.T) {
+	for _, input := range []string{"", " \n", "[]", " [ \n ] \n"} {
+		dec := protojson.NewDecoder(strings.NewReader(input))
+		if err := dec.Decode(&pb3.Scalars{}); err != io.EOF {
+			t.Errorf("Decode(%q) = %v, want io.EOF", input, err)
+		}
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		input   string
+		decoded int    // number of messages decoded before the error
+		wantErr string // expected error substring
+		next    bool   // the following message is decoded
+	}{{
+		desc:    "unknown field",
+		input:   "{}\n{\"sInt32\": 1,\n \"unknown\": 2}\n{}",
+		decoded: 1,
+		wantErr: `(line 3:2): unknown field "unknown"`,
+		next:    true,
+	}, {
+		desc:    "invalid value in array",
+		input:   "[{},\n  {\"sInt32\": \"x\"}, {}]",
+		decoded: 1,
+		wantErr: `(line 2:14): invalid value for int32 type: "x"`,
+		next:    true,
+	}, {
+		desc:    "syntax error in value",
+		input:   "{}\n  {\"sInt32\" 1}",
+		decoded: 1,
+		wantErr: `syntax error (line 2:13): unexpected character 1, missing ":" after field name`,
+	}, {
+		desc:    "missing comma",
+		input:   "[{}\n {}]",
+		decoded: 1,
+		wantErr: `syntax error (line 2:2): unexpected character '{', want "," or "]"`,
+	}, {
+		desc:    "trailing comma",
+		input:   "[{},\n]",
+		decoded: 1,
+		wantErr: "syntax error (line 2:1): unexpected character ']'",
+	}, {
+		desc:    "after array",
+		input:   "[{}]\n{}",
+		decoded: 1,
+		wantErr: "syntax error (line 2:1): unexpected character '{' after the end of the array",
+	}, {
+		desc:    "unterminated array",
+		input:   "[{}, {}",
+		decoded: 2,
+		wantErr: "unexpected EOF",
+	}, {
+		desc:    "unterminated object",
+		input:   "{}\n{\"sString\": \"}",
+		decoded: 1,
+		wantErr: "unexpected EOF",
+	}, {
+		desc:    "unexpected close",
+		input:   "{}\n}",
+		decoded: 1,
+		wantErr: "syntax error (line 2:1): unexpected character '}'",
+	}}
+	for _, test := range tests {
+		dec := protojson.NewDecoder(strings.NewReader(test.input))
+		for i := 0; i < test.decoded; i++ {
+			if err := dec.Decode(&pb3.Scalars{}); err != nil {
+				t.Fatalf("%s: Decode error: %v", test.desc, err)
+			}
+		}
+		err := dec.Decode(&pb3.Scalars{})
+		if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%s: Decode error = %v, want %q", test.desc, err, test.wantErr)
+			continue
+		}
+		err = dec.Decode(&pb3.Scalars{})
+		if test.next && err != nil {
+			t.Errorf("%s: Decode after error = %v, want nil", test.desc, err)
+		}
+		if !test.next && err == nil {
+			t.Errorf("%s: Decode after error succeeded, want error", test.desc)
+		}
+	}
+}
@@ -657,6 +657,35 @@ func TestUnmarshal(t *testing.T) {
   "s_string": "proto name used"
 }`,
 		wantErr: `(line 3:3): duplicate field "s_string"`,
+	}, {
+		desc:         "FieldNamer",
+		umo:          protojson.UnmarshalOptions{FieldNamer: pascalCase},
+		inputMessage: &pb3.Nests{},
+		inputText: `{
+  "SNested": {"SString": "PascalCase used"}
+}`,
+		wantMessage: &pb3.Nests{
+			SNested: &pb3.Nested{SString: "PascalCase used"},
+		},
+	}, {
+		desc:         "FieldNamer with json_name and proto name",
+		umo:          protojson.UnmarshalOptions{FieldNamer: pascalCase},
+		inputMessage: &pb3.Nests{},
+		inputText: `{
+  "sNested": {"s_string": "proto name used"}
+}`,
+		wantMessage: &pb3.Nests{
+			SNested: &pb3.Nested{SString: "proto name used"},
+		},
+	}, {
+		desc:         "FieldNamer and json_name",
+		umo:          protojson.UnmarshalOptions{FieldNamer: pascalCase},
+		inputMessage: &pb3.JSONNames{},
+		inputText: `{
+  "foo_bar": "json_name used",
+  "SString": "PascalCase used"
+}`,
+		wantErr: `(line 3:3): duplicate field "SString"`,
 	}, {
 		desc:         "duplicate field names",
 		inputMessage: &pb3.JSONNames{},
@@ -2673,6 +2702,116 @@ func TestUnmarshal(t *testing.T) {
 				10: 101,
 			},
 		},
+	}, {
+		desc:         "LenientEnumCase",
+		inputMessage: &pb3.Enums{},
+		inputText: `{
+  "sEnum": "one",
+  "sNestedEnum": "Dos"
+}`,
+		umo: protojson.UnmarshalOptions{Lenient: protojson.LenientEnumCase},
+		wantMessage: &pb3.Enums{
+			SEnum:       pb3.Enum_ONE,
+			SNestedEnum: pb3.Enums_DOS,
+		},
+	}, {
+		desc:         "LenientEnumCase: unknown enum name",
+		inputMessage: &pb3.Enums{},
+		inputText: `{
+  "sEnum": "uno"
+}`,
+		umo:     protojson.UnmarshalOptions{Lenient: protojson.LenientEnumCase},
+		wantErr: `invalid value for enum type: "uno"`,
+	}, {
+		desc:         "LenientEnumNumberStrings",
+		inputMessage: &pb2.Enums{},
+		inputText: `{
+  "rptEnum": ["1", "TEN", "42", 2]
+}`,
+		umo: protojson.UnmarshalOptions{Lenient: protojson.LenientEnumNumberStrings},
+		wantMessage: &pb2.Enums{
+			RptEnum: []pb2.Enum{pb2.Enum_ONE, pb2.Enum_TEN, 42, pb2.Enum_TWO},
+		},
+	}, {
+		desc:         "LenientEnumNumberStrings: not an int32",
+		inputMessage: &pb3.Enums{},
+		inputText: `{
+  "sEnum": "4294967296"
+}`,
+		umo:     protojson.UnmarshalOptions{Lenient: protojson.LenientEnumNumberStrings},
+		wantErr: `invalid value for enum type: "4294967296"`,
+	}, {
+		desc:         "LenientEnumCase without LenientEnumNumberStrings",
+		inputMessage: &pb3.Enums{},
+		inputText: `{
+  "sEnum": "1"
+}`,
+		umo:     protojson.UnmarshalOptions{Lenient: protojson.LenientEnumCase},
+		wantErr: `invalid value for enum type: "1"`,
+	}, {
+		desc:         "LenientDuplicateFields",
+		inputMessage: &pb3.Nests{},
+		inputText: `{
+  "sNested": {"sString": "first", "sNested": {}},
+  "s_nested": {"sString": "second", "s_string": "last"}
+}`,
+		umo: protojson.UnmarshalOptions{Lenient: protojson.LenientDuplicateFields},
+		wantMessage: &pb3.Nests{
+			SNested: &pb3.Nested{SString: "last"},
+		},
+	}, {
+		desc:         "LenientDuplicateFields: repeated and map fields",
+		inputMessage: &pb3.Maps{},
+		inputText: `{
+  "int32ToStr": {"0": "cero", "1": "uno", "0": "zero"},
+  "boolToUint32": {"true": 1},
+  "boolToUint32": {"false": 0}
+}`,
+		umo: protojson.UnmarshalOptions{Lenient: protojson.LenientDuplicateFields},
+		wantMessage: &pb3.Maps{
+			Int32ToStr:   map[int32]string{0: "zero", 1: "uno"},
+			BoolToUint32: map[bool]uint32{false: 0},
+		},
+	}, {
+		desc:         "LenientDuplicateFields: null",
+		inputMessage: &pb3.Repeats{},
+		inputText: `{
+  "rptString": ["a"],
+  "rptString": null
+}`,
+		umo:         protojson.UnmarshalOptions{Lenient: protojson.LenientDuplicateFields},
+		wantMessage: &pb3.Repeats{},
+	}, {
+		desc:         "LenientDuplicateFields: oneof",
+		inputMessage: &pb3.Oneofs{},
+		inputText: `{
+  "oneofString": "first",
+  "oneofString": "last"
+}`,
+		umo: protojson.UnmarshalOptions{Lenient: protojson.LenientDuplicateFields},
+		wantMessage: &pb3.Oneofs{
+			Union: &pb3.Oneofs_OneofString{OneofString: "last"},
+		},
+	}, {
+		desc:         "LenientDuplicateFields: different oneof fields",
+		inputMessage: &pb3.Oneofs{},
+		inputText: `{
+  "oneofString": "first",
+  "oneofEnum": "ONE"
+}`,
+		umo:     protojson.UnmarshalOptions{Lenient: protojson.LenientDuplicateFields},
+		wantErr: `(line 3:3): error parsing "oneofEnum", oneof pb3.Oneofs.union is already set`,
+	}, {
+		desc:         "LenientAll",
+		inputMessage: &pb3.Enums{},
+		inputText: `{
+  "sEnum": "1",
+  "sEnum": "two"
+}`,
+		umo: protojson.UnmarshalOptions{Lenient: protojson.LenientAll},
+		wantMessage: &pb3.Enums{
+			SEnum: pb3.Enum_TWO,
+		},
 	}, {
 		desc:         "weak fields",
 		inputMessage: &testpb.TestWeak{},
@@ -7,6 +7,7 @@ package protojson
 import (
 	"encoding/base64"
 	"fmt"
+	"io"
 
 	"github.com/golang/protobuf/protobuf/internal/encoding/json"
 	"github.com/golang/protobuf/protobuf/internal/encoding/messageset"
@@ -62,6 +63,12 @@ type MarshalOptions struct {
 	// field names.
 	UseProtoNames bool
 
+	// FieldNamer, if set, provides the JSON name of each non-extension field,
+	// taking precedence over UseProtoNames. The names it returns must be
+	// unique within a message. Output produced with a FieldNamer can be read
+	// by an Unmarshal with the same function as UnmarshalOptions.FieldNamer.
+	FieldNamer func(protoreflect.FieldDescriptor) string
+
 	// UseEnumNumbers emits enum values as numbers.
 	UseEnumNumbers bool
 
@@ -106,6 +113,14 @@ type MarshalOptions struct {
 		protoregistry.ExtensionTypeResolver
 		protoregistry.MessageTypeResolver
 	}
+
+	// Marshalers provides custom JSON encodings for messages, keyed by
+	// the full name of the message type. Each function returns the JSON value
+	// for a message of that type, which is reformatted according to the other
+	// options. It takes precedence over the encoding of well-known types.
+	// A message with a custom encoding is embedded in a google.protobuf.Any
+	// as a "value" field, as well-known types are.
+	Marshalers map[protoreflect.FullName]func(proto.Message) ([]byte, error)
 }
 
 // Format formats the message as a string.
@@ -125,19 +140,36 @@ func (o MarshalOptions) Format(m proto.Message) string {
 // MarshalOptions. Do not depend on the output being stable. It may change over
 // time across different versions of the program.
 func (o MarshalOptions) Marshal(m proto.Message) ([]byte, error) {
-	return o.marshal(nil, m)
+	return o.marshal(nil, nil, m)
 }
 
 // MarshalAppend appends the JSON format encoding of m to b,
 // returning the result.
 func (o MarshalOptions) MarshalAppend(b []byte, m proto.Message) ([]byte, error) {
-	return o.marshal(b, m)
+	return o.marshal(b, nil, m)
+}
+
+// MarshalTo writes the JSON format encoding of m to w, as Marshal returns it.
+// The output is written to w as it is produced, rather than held in memory.
+// Missing required fields are reported before any output is written,
+// but other errors may occur after part of the output has been written.
+func (o MarshalOptions) MarshalTo(w io.Writer, m proto.Message) error {
+	if m != nil && !o.AllowPartial {
+		if err := proto.CheckInitialized(m); err != nil {
+			return err
+		}
+		o.AllowPartial = true
+	}
+	_, err := o.marshal(nil, w, m)
+	return err
 }
 
 // marshal is a centralized function that all marshal operations go through.
 // For profiling purposes, avoid changing the name of this function or
 // introducing other code paths for marshal that do not go through this.
-func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
+//
+// If w is not nil, the output is written to it, and b is used as a buffer.
+func (o MarshalOptions) marshal(b []byte, w io.Writer, m proto.Message) ([]byte, error) {
 	if o.Multiline && o.Indent == "" {
 		o.Indent = defaultIndent
 	}
@@ -149,17 +181,25 @@ func (o MarshalOptions) marshal(b []byte, m proto.Message) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if w != nil {
+		internalEnc.SetWriter(w)
+	}
 
 	// Treat nil message interface as an empty message,
 	// in which case the output in an empty JSON object.
 	if m == nil {
-		return append(b, '{', '}'), nil
+		internalEnc.StartObject()
+		internalEnc.EndObject()
+		return internalEnc.Bytes(), internalEnc.Flush()
 	}
 
 	enc := encoder{internalEnc, o}
 	if err := enc.marshalMessage(m.ProtoReflect(), ""); err != nil {
 		return nil, err
 	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
 	if o.AllowPartial {
 		return enc.Bytes(), nil
 	}
@@ -235,7 +275,7 @@ func (e encoder) marshalMessage(m protoreflect.Message, typeURL string) error {
 		return errors.New("no support for proto1 MessageSets")
 	}
 
-	if marshal := wellKnownTypeMarshaler(m.Descriptor().FullName()); marshal != nil {
+	if marshal := e.typeMarshaler(m.Descriptor().FullName()); marshal != nil {
 		return marshal(e, m)
 	}
 
@@ -256,7 +296,10 @@ func (e encoder) marshalMessage(m protoreflect.Message, typeURL string) error {
 	var err error
 	order.RangeFields(fields, order.IndexNameFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
 		name := fd.JSONName()
-		if e.opts.UseProtoNames {
+		switch {
+		case e.opts.FieldNamer != nil && !fd.IsExtension():
+			name = e.opts.FieldNamer(fd)
+		case e.opts.UseProtoNames:
 			name = fd.TextName()
 		}
 
@@ -0,0 +1,52 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/protobuf/proto"
+)
+
+// Encoder writes a sequence of messages in JSON format to an output stream,
+// each followed by a newline. Unless Multiline or Indent is set, the output
+// is newline-delimited JSON (NDJSON). Either way, it may be read by a Decoder.
+type Encoder struct {
+	opts MarshalOptions
+	w    io.Writer
+	buf  []byte // reused between messages
+}
+
+// NewEncoder returns an Encoder writing messages to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return MarshalOptions{}.NewEncoder(w)
+}
+
+// NewEncoder returns an Encoder writing messages to w
+// using options in the MarshalOptions object.
+func (o MarshalOptions) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{opts: o, w: w}
+}
+
+// Encode writes the JSON format encoding of m to the stream, as MarshalTo
+// does, followed by a newline.
+func (e *Encoder) Encode(m proto.Message) error {
+	o := e.opts
+	if m != nil && !o.AllowPartial {
+		if err := proto.CheckInitialized(m); err != nil {
+			return err
+		}
+		o.AllowPartial = true
+	}
+	b, err := o.marshal(e.buf[:0], e.w, m)
+	if err != nil {
+		return err
+	}
+	e.buf = b[:0]
+	_, err = e.w.Write(newline)
+	return err
+}
+
+var newline = []byte{'\n'}
@@ -0,0 +1,124 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protojson_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/protobuf/encoding/protojson"
+	"github.com/golang/protobuf/protobuf/proto"
+
+	pb2 "github.com/golang/protobuf/protobuf/internal/testprotos/textpb2"
+	pb3 "github.com/golang/protobuf/protobuf/internal/testprotos/textpb3"
+)
+
+// chunkWriter records what is written to it, and the size of the largest write.
+type chunkWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (w *chunkWriter) Write(b []byte) (int, error) {
+	if len(b) > w.max {
+		w.max = len(b)
+	}
+	return w.buf.Write(b)
+}
+
+func TestMarshalTo(t *testing.T) {
+	m := &pb3.Repeats{}
+	for i := 0; i < 5000; i++ {
+		m.RptString = append(m.RptString, fmt.Sprintf("string %d", i))
+		m.RptInt64 = append(m.RptInt64, int64(i))
+	}
+	for _, opts := range []protojson.MarshalOptions{
+		{},
+		{Multiline: true},
+		{Indent: "\t", UseProtoNames: true},
+		{EmitUnpopulated: true},
+	} {
+		for _, m := range []proto.Message{nil, &pb3.Scalars{}, &pb3.Scalars{SString: "x"}, m} {
+			want, err := opts.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			w := &chunkWriter{}
+			if err := opts.MarshalTo(w, m); err != nil {
+				t.Fatalf("MarshalTo error: %v", err)
+			}
+			if got := w.buf.Bytes(); !bytes.Equal(got, want) {
+				t.Errorf("%+v: MarshalTo and Marshal disagree:\ngot:  %.100s\nwant: %.100s", opts, got, want)
+			}
+			if len(want) > 100000 && w.max >= len(want)/2 {
+				t.Errorf("%+v: MarshalTo wrote %v bytes at once, want it to stream the %v bytes", opts, w.max, len(want))
+			}
+		}
+	}
+}
+
+func TestMarshalToErrors(t *testing.T) {
+	// Nothing is written if a required field is missing.
+	var buf bytes.Buffer
+	if err := (protojson.MarshalOptions{}).MarshalTo(&buf, &pb2.Requireds{}); err == nil {
+		t.Errorf("MarshalTo with missing required fields succeeded, want error")
+	}
+	if buf.Len() > 0 {
+		t.Errorf("MarshalTo with missing required fields wrote %q", buf.Bytes())
+	}
+
+	wantErr := errors.New("write error")
+	if err := (protojson.MarshalOptions{}).MarshalTo(errWriter{wantErr}, &pb3.Scalars{SString: "x"}); err != wantErr {
+		t.Errorf("MarshalTo to a failing writer = %v, want %v", err, wantErr)
+	}
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestEncoder(t *testing.T) {
+	msgs := []proto.Message{
+		&pb3.Scalars{SInt32: 1},
+		&pb3.Scalars{},
+		&pb3.Scalars{SString: "line\nbreak"},
+	}
+	for _, opts := range []protojson.MarshalOptions{{}, {Multiline: true}} {
+		var buf bytes.Buffer
+		enc := opts.NewEncoder(&buf)
+		for _, m := range msgs {
+			if err := enc.Encode(m); err != nil {
+				t.Fatalf("Encode error: %v", err)
+			}
+		}
+		if !opts.Multiline {
+			if got, want := strings.Count(buf.String(), "\n"), len(msgs); got != want {
+				t.Errorf("Encode wrote %v lines, want %v:\n%s", got, want, buf.String())
+			}
+		}
+
+		dec := protojson.NewDecoder(&buf)
+		for i := 0; ; i++ {
+			got := &pb3.Scalars{}
+			err := dec.Decode(got)
+			if err == io.EOF {
+				if i != len(msgs) {
+					t.Errorf("decoded %v messages, want %v", i, len(msgs))
+				}
+				break
+			}
+			if err != nil {
+				t.Fatalf("Decode error: %v", err)
+			}
+			if i >= len(msgs) || !proto.Equal(got, msgs[i]) {
+				t.Errorf("message %v = %v", i, got)
+			}
+		}
+	}
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/golang/protobuf/protobuf/encoding/protojson"
 	"github.com/golang/protobuf/protobuf/internal/detrand"
 	"github.com/golang/protobuf/protobuf/internal/flags"
+	"github.com/golang/protobuf/protobuf/internal/strs"
 	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
 	"github.com/golang/protobuf/protobuf/reflect/protoregistry"
 	"github.com/golang/protobuf/protobuf/testing/protopack"
 
@@ -907,6 +909,30 @@ func TestMarshal(t *testing.T) {
 		},
 		want: `{
   "foo_bar": "json_name"
+}`,
+	}, {
+		desc: "FieldNamer overrides json_name",
+		mo: protojson.MarshalOptions{
+			FieldNamer: func(fd protoreflect.FieldDescriptor) string { return string(fd.Name()) },
+		},
+		input: &pb3.JSONNames{
+			SString: "proto name",
+		},
+		want: `{
+  "s_string": "proto name"
+}`,
+	}, {
+		desc: "FieldNamer with PascalCase",
+		mo:   protojson.MarshalOptions{FieldNamer: pascalCase, UseProtoNames: true},
+		input: &pb3.Nests{
+			SNested: &pb3.Nested{
+				SString: "nested",
+			},
+		},
+		want: `{
+  "SNested": {
+    "SString": "nested"
+  }
 }`,
 	}, {
 		desc: "extensions of non-repeated fields",
@@ -2527,6 +2553,11 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+// pascalCase names fields in PascalCase, as Go struct fields are.
+func pascalCase(fd protoreflect.FieldDescriptor) string {
+	return strs.GoCamelCase(string(fd.Name()))
+}
+
 func TestEncodeAppend(t *testing.T) {
 	want := []byte("prefix")
 	got := append([]byte(nil), want...)
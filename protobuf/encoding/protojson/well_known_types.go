@@ -141,7 +141,7 @@ func (e encoder) marshalAny(m protoreflect.Message) error {
 	// If type of value has custom JSON encoding, marshal out a field "value"
 	// with corresponding custom JSON encoding of the embedded message as a
 	// field.
-	if marshal := wellKnownTypeMarshaler(emt.Descriptor().FullName()); marshal != nil {
+	if marshal := e.typeMarshaler(emt.Descriptor().FullName()); marshal != nil {
 		e.StartObject()
 		defer e.EndObject()
 
@@ -176,7 +176,7 @@ func (d decoder) unmarshalAny(m protoreflect.Message) error {
 	// Use another decoder to parse the unread bytes for @type field. This
 	// avoids advancing a read from current decoder because the current JSON
 	// object may contain the fields of the embedded type.
-	dec := decoder{d.Clone(), UnmarshalOptions{RecursionLimit: d.opts.RecursionLimit}}
+	dec := decoder{d.Clone(), UnmarshalOptions{RecursionLimit: d.opts.RecursionLimit}, nil}
 	tok, err := findTypeURL(dec)
 	switch err {
 	case errEmptyObject:
@@ -207,7 +207,7 @@ func (d decoder) unmarshalAny(m protoreflect.Message) error {
 
 	// Create new message for the embedded message type and unmarshal into it.
 	em := emt.New()
-	if unmarshal := wellKnownTypeUnmarshaler(emt.Descriptor().FullName()); unmarshal != nil {
+	if unmarshal := d.typeUnmarshaler(emt.Descriptor().FullName()); unmarshal != nil {
 		// If embedded message is a custom type,
 		// unmarshal the JSON "value" field into it.
 		if err := d.unmarshalAnyValue(unmarshal, em); err != nil {
@@ -0,0 +1,381 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+
+	"github.com/golang/protobuf/protobuf/internal/errors"
+)
+
+var errRecursionDepth = errors.New("exceeded maximum recursion depth")
+
+// Scanner reads the fields of a wire-format message from an io.Reader,
+// one at a time, without buffering the message.
+//
+// Each call to Next advances to the next field, whose number, type and
+// scalar value are then reported by the accessor methods. The value of
+// a length-delimited field is read with Bytes or Reader, or descended into
+// with Enter as a sub-message, which is also how the fields of a group
+// are read. Values which are not read are skipped by the following call
+// to Next. For example:
+//
+//	s := protowire.NewScanner(r)
+//	for s.Next() {
+//		if s.Number() == 1 && s.Type() == protowire.BytesType {
+//			s.Enter()
+//			for s.Next() {
+//				// Fields of the sub-message.
+//			}
+//			s.Exit()
+//		}
+//	}
+//	if err := s.Err(); err != nil {
+//		// Handle the error.
+//	}
+type Scanner struct {
+	r   byteReader
+	off int64 // number of bytes read from r
+
+	// stack holds the messages that are being scanned, starting with the
+	// top-level message and ending with the message entered most recently.
+	stack []scanFrame
+
+	num     Number
+	typ     Type
+	val     uint64 // value of a varint or fixed field, or length of a bytes field
+	pending int64  // number of unread bytes of the value of a bytes field
+	unread  bool   // value of a bytes or group field has not been read
+	field   int64  // number of fields scanned, which invalidates readers of earlier values
+
+	err error
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+type scanFrame struct {
+	end   int64  // offset of the end of the message, or -1 if it ends with the input
+	group Number // field number of the group, or 0 if the message is not a group
+	done  bool   // all fields of the message have been read
+}
+
+// NewScanner returns a Scanner reading the message in r.
+// The message ends with the input.
+//
+// If r does not implement io.ByteReader, it is buffered,
+// so that the Scanner may read beyond the end of the message.
+func NewScanner(r io.Reader) *Scanner {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Scanner{
+		r:     br,
+		stack: []scanFrame{{end: -1}},
+	}
+}
+
+// Next advances to the next field of the current message, skipping the
+// unread value of the previous field. It returns false at the end of
+// the current message, or if an error occurs.
+func (s *Scanner) Next() bool {
+	if s.err != nil || !s.skipValue() {
+		return false
+	}
+	s.num, s.typ, s.val, s.unread = 0, 0, 0, false
+	s.field++
+	f := &s.stack[len(s.stack)-1]
+	if f.done {
+		return false
+	}
+	if f.end >= 0 && s.off == f.end {
+		if f.group > 0 {
+			return s.fail(io.ErrUnexpectedEOF)
+		}
+		f.done = true
+		return false
+	}
+	v, err := s.readVarint(f.end < 0 && f.group == 0)
+	if err == io.EOF {
+		f.done = true
+		return false
+	}
+	if err != nil {
+		return s.fail(err)
+	}
+	num, typ := DecodeTag(v)
+	if num < MinValidNumber {
+		return s.fail(errFieldNumber)
+	}
+	switch typ {
+	case VarintType:
+		s.val, err = s.readVarint(false)
+	case Fixed32Type:
+		s.val, err = s.readFixed(4)
+	case Fixed64Type:
+		s.val, err = s.readFixed(8)
+	case BytesType:
+		s.val, err = s.readVarint(false)
+		if err == nil && (s.val > uint64(maxInt64) || f.end >= 0 && int64(s.val) > f.end-s.off) {
+			err = io.ErrUnexpectedEOF
+		}
+		s.pending = int64(s.val)
+		s.unread = true
+	case StartGroupType:
+		s.unread = true
+	case EndGroupType:
+		if num != f.group {
+			return s.fail(errEndGroup)
+		}
+		f.done = true
+		return false
+	default:
+		return s.fail(errReserved)
+	}
+	if err != nil {
+		return s.fail(err)
+	}
+	s.num, s.typ = num, typ
+	return true
+}
+
+const maxInt64 = 1<<63 - 1
+
+// Number returns the field number of the current field.
+func (s *Scanner) Number() Number {
+	return s.num
+}
+
+// Type returns the wire type of the current field.
+func (s *Scanner) Type() Type {
+	return s.typ
+}
+
+// Varint returns the value of the current field, which must be a VarintType.
+func (s *Scanner) Varint() uint64 {
+	if s.typ != VarintType {
+		return 0
+	}
+	return s.val
+}
+
+// Fixed32 returns the value of the current field, which must be a Fixed32Type.
+func (s *Scanner) Fixed32() uint32 {
+	if s.typ != Fixed32Type {
+		return 0
+	}
+	return uint32(s.val)
+}
+
+// Fixed64 returns the value of the current field, which must be a Fixed64Type.
+func (s *Scanner) Fixed64() uint64 {
+	if s.typ != Fixed64Type {
+		return 0
+	}
+	return s.val
+}
+
+// Len returns the length of the value of the current field,
+// which must be a BytesType.
+func (s *Scanner) Len() int64 {
+	if s.typ != BytesType {
+		return 0
+	}
+	return int64(s.val)
+}
+
+// Bytes reads the remainder of the value of the current field,
+// which must be a BytesType.
+func (s *Scanner) Bytes() ([]byte, error) {
+	if s.typ != BytesType {
+		return nil, errors.New("field %v is not of bytes type", s.num)
+	}
+	return io.ReadAll(s.Reader())
+}
+
+// Reader returns a reader of the remainder of the value of the current
+// field, which must be a BytesType. The reader is valid until the next
+// call to Next or Enter.
+func (s *Scanner) Reader() io.Reader {
+	if s.typ != BytesType {
+		return valueReader{}
+	}
+	s.unread = false
+	return valueReader{s, s.field}
+}
+
+type valueReader struct {
+	s     *Scanner
+	field int64
+}
+
+func (r valueReader) Read(b []byte) (int, error) {
+	s := r.s
+	if s == nil || s.field != r.field {
+		return 0, io.EOF
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	if s.pending == 0 {
+		return 0, io.EOF
+	}
+	if int64(len(b)) > s.pending {
+		b = b[:s.pending]
+	}
+	n, err := s.r.Read(b)
+	s.off += int64(n)
+	s.pending -= int64(n)
+	if err == io.EOF && s.pending > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil && err != io.EOF {
+		s.err = err
+	}
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Enter descends into the value of the current field, which must be
+// an unread BytesType or a StartGroupType, as a message. Next then
+// advances over the fields of that message, until Exit is called.
+func (s *Scanner) Enter() error {
+	if s.err != nil {
+		return s.err
+	}
+	if !s.unread {
+		return errors.New("field %v has no unread message value", s.num)
+	}
+	if len(s.stack) > DefaultRecursionLimit {
+		s.fail(errRecursionDepth)
+		return s.err
+	}
+	f := scanFrame{end: s.stack[len(s.stack)-1].end}
+	switch s.typ {
+	case BytesType:
+		f.end = s.off + s.pending
+		s.pending = 0
+	case StartGroupType:
+		f.group = s.num
+	}
+	s.stack = append(s.stack, f)
+	s.num, s.typ, s.val, s.unread = 0, 0, 0, false
+	s.field++
+	return nil
+}
+
+// Exit skips the unread fields of the message entered by the last call
+// to Enter, and returns to the enclosing message. Next then advances to
+// the field which follows the entered one.
+// Exit panics if there is no entered message.
+func (s *Scanner) Exit() error {
+	if len(s.stack) <= 1 {
+		panic("protowire: Exit called without Enter")
+	}
+	for s.Next() {
+	}
+	if s.err != nil {
+		return s.err
+	}
+	s.stack = s.stack[:len(s.stack)-1]
+	return nil
+}
+
+// Depth returns the number of entered messages.
+func (s *Scanner) Depth() int {
+	return len(s.stack) - 1
+}
+
+// Err returns the first error that occurred while scanning, if any.
+// It is nil if scanning stopped at the end of the input.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+func (s *Scanner) fail(err error) bool {
+	if s.err == nil {
+		s.err = err
+	}
+	s.num, s.typ, s.val, s.unread = 0, 0, 0, false
+	return false
+}
+
+// skipValue skips the unread value of the current field.
+func (s *Scanner) skipValue() bool {
+	if s.typ == StartGroupType && s.unread {
+		if s.Enter() != nil || s.Exit() != nil {
+			return false
+		}
+	}
+	if s.pending > 0 {
+		n, err := io.CopyN(io.Discard, s.r, s.pending)
+		s.off += n
+		s.pending -= n
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return s.fail(err)
+		}
+	}
+	return true
+}
+
+// readVarint reads a varint. It returns io.EOF if eofOK is set and
+// the input ends before the varint.
+func (s *Scanner) readVarint(eofOK bool) (uint64, error) {
+	var v uint64
+	for i := 0; ; i++ {
+		c, err := s.readByte()
+		if err == io.EOF && (i > 0 || !eofOK) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		if i == 9 && c > 1 {
+			return 0, errOverflow
+		}
+		v |= uint64(c&0x7f) << (7 * uint(i))
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+}
+
+func (s *Scanner) readFixed(n int) (uint64, error) {
+	var b [8]byte
+	for i := 0; i < n; i++ {
+		c, err := s.readByte()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		b[i] = c
+	}
+	return binary.LittleEndian.Uint64(b[:]), nil
+}
+
+// readByte reads a byte of the current message.
+func (s *Scanner) readByte() (byte, error) {
+	if end := s.stack[len(s.stack)-1].end; end >= 0 && s.off >= end {
+		return 0, io.ErrUnexpectedEOF
+	}
+	c, err := s.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	s.off++
+	return c, nil
+}
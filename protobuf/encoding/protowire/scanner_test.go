@@ -0,0 +1,233 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protowire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// scanAll scans the message read from r, entering every bytes field whose number
+// is in enter, and returns the tokens it reads.
+func scanAll(t *testing.T, r io.Reader, enter map[Number]bool) ([]string, error) {
+	t.Helper()
+	var tokens []string
+	s := NewScanner(r)
+	var scan func() error
+	scan = func() error {
+		for s.Next() {
+			switch s.Type() {
+			case VarintType:
+				tokens = append(tokens, fmt.Sprintf("%d:varint=%d", s.Number(), s.Varint()))
+			case Fixed32Type:
+				tokens = append(tokens, fmt.Sprintf("%d:fixed32=%d", s.Number(), s.Fixed32()))
+			case Fixed64Type:
+				tokens = append(tokens, fmt.Sprintf("%d:fixed64=%d", s.Number(), s.Fixed64()))
+			case BytesType:
+				if enter[s.Number()] {
+					tokens = append(tokens, fmt.Sprintf("%d:{", s.Number()))
+					if err := s.Enter(); err != nil {
+						return err
+					}
+					if err := scan(); err != nil {
+						return err
+					}
+					if err := s.Exit(); err != nil {
+						return err
+					}
+					tokens = append(tokens, "}")
+					continue
+				}
+				b, err := s.Bytes()
+				if err != nil {
+					return err
+				}
+				tokens = append(tokens, fmt.Sprintf("%d:bytes=%q", s.Number(), b))
+			case StartGroupType:
+				tokens = append(tokens, fmt.Sprintf("%d:group{", s.Number()))
+				if err := s.Enter(); err != nil {
+					return err
+				}
+				if err := scan(); err != nil {
+					return err
+				}
+				if err := s.Exit(); err != nil {
+					return err
+				}
+				tokens = append(tokens, "}")
+			}
+		}
+		return s.Err()
+	}
+	err := scan()
+	return tokens, err
+}
+
+func testMessage() []byte {
+	var inner []byte
+	inner = AppendTag(inner, 1, VarintType)
+	inner = AppendVarint(inner, 300)
+	inner = AppendTag(inner, 2, BytesType)
+	inner = AppendString(inner, "inner")
+
+	var group []byte
+	group = AppendTag(group, 1, Fixed32Type)
+	group = AppendFixed32(group, 7)
+	group = AppendTag(group, 4, EndGroupType)
+
+	var b []byte
+	b = AppendTag(b, 1, VarintType)
+	b = AppendVarint(b, 150)
+	b = AppendTag(b, 2, Fixed64Type)
+	b = AppendFixed64(b, 1<<40)
+	b = AppendTag(b, 3, BytesType)
+	b = AppendBytes(b, inner)
+	b = AppendTag(b, 4, StartGroupType)
+	b = append(b, group...)
+	b = AppendTag(b, 5, BytesType)
+	b = AppendString(b, "last")
+	return b
+}
+
+func TestScanner(t *testing.T) {
+	b := testMessage()
+	for _, test := range []struct {
+		desc  string
+		enter map[Number]bool
+		want  []string
+	}{{
+		desc:  "enter sub-message",
+		enter: map[Number]bool{3: true},
+		want: []string{
+			"1:varint=150",
+			"2:fixed64=1099511627776",
+			"3:{", "1:varint=300", `2:bytes="inner"`, "}",
+			"4:group{", "1:fixed32=7", "}",
+			`5:bytes="last"`,
+		},
+	}, {
+		desc: "read sub-message as bytes",
+		want: []string{
+			"1:varint=150",
+			"2:fixed64=1099511627776",
+			`3:bytes="\b\xac\x02\x12\x05inner"`,
+			"4:group{", "1:fixed32=7", "}",
+			`5:bytes="last"`,
+		},
+	}} {
+		for _, r := range []struct {
+			desc string
+			r    io.Reader
+		}{
+			{"bytes.Reader", bytes.NewReader(b)},
+			{"one byte reader", iotest.OneByteReader(bytes.NewReader(b))},
+		} {
+			got, err := scanAll(t, r.r, test.enter)
+			if err != nil {
+				t.Errorf("%s, %s: scan error: %v", test.desc, r.desc, err)
+			}
+			if strings.Join(got, " ") != strings.Join(test.want, " ") {
+				t.Errorf("%s, %s: tokens mismatch\ngot:  %v\nwant: %v", test.desc, r.desc, got, test.want)
+			}
+		}
+	}
+}
+
+func TestScannerSkip(t *testing.T) {
+	s := NewScanner(bytes.NewReader(testMessage()))
+	var nums []Number
+	for s.Next() {
+		nums = append(nums, s.Number())
+		if s.Number() == 3 {
+			// Leave the sub-message after its first field.
+			if err := s.Enter(); err != nil {
+				t.Fatalf("Enter() error: %v", err)
+			}
+			if !s.Next() || s.Varint() != 300 || s.Depth() != 1 {
+				t.Fatalf("first field of sub-message = %v, %v, want 300", s.Number(), s.Varint())
+			}
+			if err := s.Exit(); err != nil {
+				t.Fatalf("Exit() error: %v", err)
+			}
+		}
+		if s.Number() == 5 {
+			// Read part of the value, leaving the rest to be skipped.
+			var buf [2]byte
+			if _, err := io.ReadFull(s.Reader(), buf[:]); err != nil || string(buf[:]) != "la" {
+				t.Fatalf("Reader() read %q, %v, want %q", buf[:], err, "la")
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	if got, want := fmt.Sprint(nums), "[1 2 3 4 5]"; got != want {
+		t.Errorf("field numbers = %v, want %v", got, want)
+	}
+}
+
+func TestScannerLargeValue(t *testing.T) {
+	// A value is streamed without being buffered.
+	const size = 1 << 20
+	var b []byte
+	b = AppendTag(b, 1, BytesType)
+	b = AppendVarint(b, size)
+	r := io.MultiReader(bytes.NewReader(b), io.LimitReader(zeros{}, size))
+	s := NewScanner(r)
+	if !s.Next() || s.Len() != size {
+		t.Fatalf("Next(), Len() = %v, want true, %v", s.Len(), size)
+	}
+	n, err := io.Copy(io.Discard, s.Reader())
+	if n != size || err != nil {
+		t.Fatalf("io.Copy(Reader()) = %v, %v, want %v, nil", n, err, size)
+	}
+	if s.Next() || s.Err() != nil {
+		t.Fatalf("Next(), Err() = true, %v, want false, nil", s.Err())
+	}
+}
+
+type zeros struct{}
+
+func (zeros) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func TestScannerErrors(t *testing.T) {
+	valid := testMessage()
+	tests := []struct {
+		desc string
+		in   []byte
+		want error
+	}{
+		{"reserved wire type", AppendTag(nil, 1, 6), errReserved},
+		{"field number zero", AppendTag(nil, 0, VarintType), errFieldNumber},
+		{"unmatched end group", AppendTag(nil, 1, EndGroupType), errEndGroup},
+		{"mismatched end group", AppendTag(AppendTag(nil, 1, StartGroupType), 2, EndGroupType), errEndGroup},
+		{"varint overflow", append(AppendTag(nil, 1, VarintType), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x02), errOverflow},
+		{"value exceeds sub-message", AppendBytes(AppendTag(nil, 1, BytesType), AppendTag(AppendTag(nil, 1, BytesType), 5, VarintType)), io.ErrUnexpectedEOF},
+		{"unterminated group", AppendTag(nil, 1, StartGroupType), io.ErrUnexpectedEOF},
+	}
+	// These prefixes of the message do not end on a field boundary.
+	for _, n := range []int{1, 2, 5, 11, 14, len(valid) - 1} {
+		tests = append(tests, struct {
+			desc string
+			in   []byte
+			want error
+		}{fmt.Sprintf("truncated to %v bytes", n), valid[:n], io.ErrUnexpectedEOF})
+	}
+	for _, test := range tests {
+		_, err := scanAll(t, bytes.NewReader(test.in), map[Number]bool{1: true, 3: true})
+		if err != test.want {
+			t.Errorf("%s: scan error = %v, want %v", test.desc, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package arena implements bulk allocation of the values of unmarshaled
+// messages.
+package arena
+
+import "github.com/golang/protobuf/protobuf/internal/pragma"
+
+const (
+	minChunkBytes = 1 << 10
+	maxChunkBytes = 64 << 10
+)
+
+// ProtoInternal marks *Arena as the implementation of protoiface.Arena.
+func (*Arena) ProtoInternal(pragma.DoNotImplement) {}
+
+// Bytes returns a copy of b.
+// The capacity of the copy equals its length, so that appending to it
+// never overwrites other values of the arena.
+func (a *Arena) Bytes(b []byte) []byte {
+	s := a.bytes.alloc(len(b))
+	copy(s, b)
+	return s
+}
+
+// byteSlab allocates byte slices out of chunks.
+// Its values are not cleared, since they are always overwritten.
+type byteSlab struct {
+	chunks [][]byte
+	chunk  int
+	next   int
+}
+
+func (s *byteSlab) alloc(n int) []byte {
+	for s.chunk < len(s.chunks) {
+		c := s.chunks[s.chunk]
+		if i := s.next; i+n <= len(c) {
+			s.next += n
+			return c[i : i+n : i+n]
+		}
+		s.chunk++
+		s.next = 0
+	}
+	chunkLen := chunkLen(1, len(s.chunks), n)
+	if chunkLen < 0 {
+		return make([]byte, n)
+	}
+	s.chunks = append(s.chunks, make([]byte, chunkLen))
+	s.chunk = len(s.chunks) - 1
+	s.next = n
+	return s.chunks[s.chunk][:n:n]
+}
+
+func (s *byteSlab) reset() {
+	s.chunk = 0
+	s.next = 0
+}
+
+// chunkLen returns the length of a new chunk of values of the given size
+// that holds at least n values, where numChunks chunks already exist.
+// It returns -1 if n values are too large to share a chunk.
+func chunkLen(size, numChunks, n int) int {
+	if size == 0 {
+		size = 1
+	}
+	if n*size > maxChunkBytes {
+		return -1
+	}
+	chunkBytes := maxChunkBytes
+	if numChunks < 16 && minChunkBytes<<uint(numChunks) < maxChunkBytes {
+		chunkBytes = minChunkBytes << uint(numChunks)
+	}
+	if chunkLen := chunkBytes / size; chunkLen > n {
+		return chunkLen
+	}
+	return n
+}
@@ -0,0 +1,24 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build purego || appengine
+// +build purego appengine
+
+package arena
+
+// Arena allocates bytes out of chunks, which are released together by Reset
+// and then reused by later allocations. Without package unsafe, it does not
+// allocate other values.
+//
+// The zero value is ready for use. An Arena must not be used concurrently.
+type Arena struct {
+	bytes byteSlab
+}
+
+// Reset releases all values allocated by a.
+// The memory of the values is reused by later allocations,
+// so none of them may be used after Reset is called.
+func (a *Arena) Reset() {
+	a.bytes.reset()
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !purego && !appengine
+// +build !purego,!appengine
+
+package arena
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// Arena allocates values out of slabs, one per type, which are released
+// together by Reset and then reused by later allocations.
+//
+// The zero value is ready for use. An Arena must not be used concurrently.
+type Arena struct {
+	slabs  map[reflect.Type]*slab
+	recent [4]*slab // most recently used slabs, which save a map lookup
+	bytes  byteSlab
+
+	// hdr is the header of a slice being copied or cleared,
+	// which is not allocated on each use.
+	hdr sliceHeader
+}
+
+type sliceHeader struct {
+	Data unsafe.Pointer
+	Len  int
+	Cap  int
+}
+
+// slab allocates the values of a single type out of chunks,
+// each of which is an array of the type.
+type slab struct {
+	typ    reflect.Type
+	slice  reflect.Type // slice of typ
+	size   uintptr
+	chunks []chunk
+	chunk  int // index of the chunk being allocated from
+	next   int // index of the next free element of the current chunk
+}
+
+type chunk struct {
+	p   unsafe.Pointer // first element
+	len int
+}
+
+// New returns a pointer to a new zero value of type t.
+func (a *Arena) New(t reflect.Type) unsafe.Pointer {
+	return a.slab(t).reserve(1)
+}
+
+// GrowSlice grows the capacity of the slice at sp, of slice type t,
+// to its length plus n. The elements are moved into the arena,
+// so that appending up to n elements to it does not allocate.
+func (a *Arena) GrowSlice(t reflect.Type, sp unsafe.Pointer, n int) {
+	s := (*sliceHeader)(sp)
+	c := s.Len + n
+	p := a.slab(t.Elem()).reserve(c)
+	if s.Len > 0 {
+		// Copy with reflect so that the write barriers of pointers are honored.
+		a.hdr = sliceHeader{p, s.Len, c}
+		reflect.Copy(reflect.NewAt(t, unsafe.Pointer(&a.hdr)).Elem(), reflect.NewAt(t, sp).Elem())
+		a.hdr = sliceHeader{}
+	}
+	*s = sliceHeader{p, s.Len, c}
+}
+
+// Reset releases all values allocated by a.
+// The memory of the values is reused by later allocations,
+// so none of them may be used after Reset is called.
+func (a *Arena) Reset() {
+	for _, s := range a.slabs {
+		a.reset(s)
+	}
+	a.bytes.reset()
+}
+
+func (a *Arena) slab(t reflect.Type) *slab {
+	for _, s := range a.recent {
+		if s != nil && s.typ == t {
+			return s
+		}
+	}
+	s := a.slabs[t]
+	if s == nil {
+		if a.slabs == nil {
+			a.slabs = make(map[reflect.Type]*slab)
+		}
+		s = &slab{typ: t, slice: reflect.SliceOf(t), size: t.Size()}
+		a.slabs[t] = s
+	}
+	copy(a.recent[1:], a.recent[:])
+	a.recent[0] = s
+	return s
+}
+
+// reserve returns a pointer to n consecutive zero values.
+func (s *slab) reserve(n int) unsafe.Pointer {
+	for s.chunk < len(s.chunks) {
+		c := s.chunks[s.chunk]
+		if i := s.next; i+n <= c.len {
+			s.next += n
+			return unsafe.Pointer(uintptr(c.p) + uintptr(i)*s.size)
+		}
+		s.chunk++
+		s.next = 0
+	}
+	chunkLen := chunkLen(int(s.size), len(s.chunks), n)
+	if chunkLen < 0 {
+		// Too large to share a chunk with other values.
+		return unsafe.Pointer(reflect.MakeSlice(s.slice, n, n).Pointer())
+	}
+	p := unsafe.Pointer(reflect.MakeSlice(s.slice, chunkLen, chunkLen).Pointer())
+	s.chunks = append(s.chunks, chunk{p, chunkLen})
+	s.chunk = len(s.chunks) - 1
+	s.next = n
+	return p
+}
+
+// reset clears the values allocated from s, both because messages must
+// start out empty and to avoid retaining memory.
+func (a *Arena) reset(s *slab) {
+	for i := 0; i < len(s.chunks) && i <= s.chunk; i++ {
+		n := s.chunks[i].len
+		if i == s.chunk {
+			n = s.next
+		}
+		if n > 0 {
+			a.hdr = sliceHeader{s.chunks[i].p, n, n}
+			clearSlice(reflect.NewAt(s.slice, unsafe.Pointer(&a.hdr)).Elem())
+			a.hdr = sliceHeader{}
+		}
+	}
+	s.chunk = 0
+	s.next = 0
+}
@@ -0,0 +1,15 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !purego && !appengine && !go1.21
+// +build !purego,!appengine,!go1.21
+
+package arena
+
+import "reflect"
+
+// clearSlice sets the elements of the slice s to their zero value.
+func clearSlice(s reflect.Value) {
+	reflect.Copy(s, reflect.MakeSlice(s.Type(), s.Len(), s.Len()))
+}
@@ -40,6 +40,19 @@ func BenchmarkWire(b *testing.B) {
 			}
 		}
 	})
+	bench(b, "UnmarshalArena", func(ds dataset, pb *testing.PB) {
+		arena := &proto.Arena{}
+		opts := proto.UnmarshalOptions{Arena: arena}
+		for pb.Next() {
+			for _, p := range ds.wire {
+				m := ds.messageType.New().Interface()
+				if err := opts.Unmarshal(p, m); err != nil {
+					b.Fatal(err)
+				}
+			}
+			arena.Reset()
+		}
+	})
 	bench(b, "Marshal", func(ds dataset, pb *testing.PB) {
 		for pb.Next() {
 			for _, m := range ds.messages {
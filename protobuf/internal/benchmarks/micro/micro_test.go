@@ -204,3 +204,43 @@ func BenchmarkRequired(b *testing.B) {
 		})
 	})
 }
+
+// BenchmarkArena tests a message containing 100 nested messages and strings.
+//
+// It compares the allocations of unmarshaling with and without an arena.
+func BenchmarkArena(b *testing.B) {
+	m := &testpb.TestAllTypes{}
+	for i := int32(0); i < 100; i++ {
+		m.RepeatedNestedMessage = append(m.RepeatedNestedMessage, &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(i),
+		})
+		m.RepeatedString = append(m.RepeatedString, "string")
+	}
+	w, err := proto.Marshal(m)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Run("Wire/Unmarshal", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if err := proto.Unmarshal(w, &testpb.TestAllTypes{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+	b.Run("Wire/UnmarshalArena", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			arena := &proto.Arena{}
+			opts := proto.UnmarshalOptions{Arena: arena}
+			for pb.Next() {
+				if err := opts.Unmarshal(w, &testpb.TestAllTypes{}); err != nil {
+					b.Fatal(err)
+				}
+				arena.Reset()
+			}
+		})
+	})
+}
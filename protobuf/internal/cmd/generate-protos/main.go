@@ -48,7 +48,11 @@ func init() {
 		// This is reasonable since we fully control the output.
 		detrand.Disable()
 
-		protogen.Options{}.Run(func(gen *protogen.Plugin) error {
+		var flags flag.FlagSet
+		flags.Var(&gengo.DefaultAPILevel, "api_level", "API of generated message types")
+		protogen.Options{
+			ParamFunc: flags.Set,
+		}.Run(func(gen *protogen.Plugin) error {
 			for _, file := range gen.Files {
 				if file.Generate {
 					gengo.GenerateVersionMarkers = false
@@ -58,6 +62,8 @@ func init() {
 				}
 			}
 			gen.SupportedFeatures = gengo.SupportedFeatures
+			gen.SupportedEditionsMinimum = gengo.SupportedEditionsMinimum
+			gen.SupportedEditionsMaximum = gengo.SupportedEditionsMaximum
 			return nil
 		})
 		os.Exit(0)
@@ -132,6 +138,7 @@ func generateLocalProtos() {
 		path     string
 		pkgPaths map[string]string // mapping of .proto path to Go package path
 		annotate map[string]bool   // .proto files to annotate
+		apiLevel map[string]string // API level of .proto files not using the default
 		exclude  map[string]bool   // .proto files to exclude from generation
 	}{{
 		path: "cmd/protoc-gen-go/testdata",
@@ -139,6 +146,10 @@ func generateLocalProtos() {
 			"cmd/protoc-gen-go/testdata/nopackage/nopackage.proto": "github.com/golang/protobuf/protobuf/cmd/protoc-gen-go/testdata/nopackage",
 		},
 		annotate: map[string]bool{"cmd/protoc-gen-go/testdata/annotations/annotations.proto": true},
+		apiLevel: map[string]string{
+			"cmd/protoc-gen-go/testdata/hybrid/hybrid.proto": "hybrid",
+			"cmd/protoc-gen-go/testdata/opaque/opaque.proto": "opaque",
+		},
 	}, {
 		path:    "internal/testprotos",
 		exclude: map[string]bool{"internal/testprotos/irregular/irregular.proto": true},
@@ -172,6 +183,9 @@ func generateLocalProtos() {
 			if d.annotate[filepath.ToSlash(relPath)] {
 				opts += ",annotate_code"
 			}
+			if level := d.apiLevel[filepath.ToSlash(relPath)]; level != "" {
+				opts += ",api_level=" + level
+			}
 			protoc("-I"+filepath.Join(protoRoot, "src"), "-I"+repoRoot, "--go_out="+opts+":"+tmpDir, relPath)
 			return nil
 		})
@@ -282,7 +282,11 @@ func consume{{.Name}}Ptr(b []byte, p pointer, wtyp protowire.Type, f *coderField
 	}
 	vp := p.{{.GoType.PointerMethod}}Ptr()
 	if *vp == nil {
-		*vp = new({{.GoType}})
+		if opts.arena != nil {
+			*vp = opts.newPointer(reflect.TypeOf((*{{.GoType}})(nil)).Elem()).{{.GoType.PointerMethod}}()
+		} else {
+			*vp = new({{.GoType}})
+		}
 	}
 	**vp = {{.ToGoType}}
 	out.n = n
@@ -324,7 +328,11 @@ func consume{{.Name}}PtrValidateUTF8(b []byte, p pointer, wtyp protowire.Type, f
 	}
 	vp := p.{{.GoType.PointerMethod}}Ptr()
 	if *vp == nil {
-		*vp = new({{.GoType}})
+		if opts.arena != nil {
+			*vp = opts.newPointer(reflect.TypeOf((*{{.GoType}})(nil)).Elem()).{{.GoType.PointerMethod}}()
+		} else {
+			*vp = new({{.GoType}})
+		}
 	}
 	**vp = {{.ToGoType}}
 	out.n = n
@@ -382,7 +390,11 @@ func consume{{.Name}}Slice(b []byte, p pointer, wtyp protowire.Type, f *coderFie
 		}
 		{{- end}}
 		if count > 0 {
-			p.grow{{.GoType.PointerMethod}}Slice(count)
+			if opts.arena != nil {
+				opts.growSlice(p, reflect.TypeOf(([]{{.GoType}})(nil)), count)
+			} else {
+				p.grow{{.GoType.PointerMethod}}Slice(count)
+			}
 		}
 		s := *sp
 		for len(b) > 0 {
@@ -405,6 +417,9 @@ func consume{{.Name}}Slice(b []byte, p pointer, wtyp protowire.Type, f *coderFie
 	if n < 0 {
 		return out, errDecode
 	}
+	if opts.arena != nil && len(*sp) == cap(*sp) {
+		opts.growFullSlice(p, reflect.TypeOf(([]{{.GoType}})(nil)))
+	}
 	*sp = append(*sp, {{.ToGoType}})
 	out.n = n
 	return out, nil
@@ -444,6 +459,9 @@ func consume{{.Name}}SliceValidateUTF8(b []byte, p pointer, wtyp protowire.Type,
 		return out, errInvalidUTF8{}
 	}
 	sp := p.{{.GoType.PointerMethod}}Slice()
+	if opts.arena != nil && len(*sp) == cap(*sp) {
+		opts.growFullSlice(p, reflect.TypeOf(([]{{.GoType}})(nil)))
+	}
 	*sp = append(*sp, {{.ToGoType}})
 	out.n = n
 	return out, nil
@@ -530,7 +548,7 @@ func consume{{.Name}}Value(b []byte, _ protoreflect.Value, _ protowire.Number, w
 		return protoreflect.Value{}, out, errDecode
 	}
 	out.n = n
-	return {{.ToValue}}, out, nil
+	return {{or .ImplToValue .ToValue}}, out, nil
 }
 
 var coder{{.Name}}Value = valueCoderFuncs{
@@ -568,7 +586,7 @@ func consume{{.Name}}ValueValidateUTF8(b []byte, _ protoreflect.Value, _ protowi
 		return protoreflect.Value{}, out, errInvalidUTF8{}
 	}
 	out.n = n
-	return {{.ToValue}}, out, nil
+	return {{or .ImplToValue .ToValue}}, out, nil
 }
 
 var coder{{.Name}}ValueValidateUTF8 = valueCoderFuncs{
@@ -618,7 +636,7 @@ func consume{{.Name}}SliceValue(b []byte, listv protoreflect.Value, _ protowire.
 			if n < 0 {
 				return protoreflect.Value{}, out, errDecode
 			}
-			list.Append({{.ToValue}})
+			list.Append({{or .ImplToValue .ToValue}})
 			b = b[n:]
 		}
 		out.n = n
@@ -632,7 +650,7 @@ func consume{{.Name}}SliceValue(b []byte, listv protoreflect.Value, _ protowire.
 	if n < 0 {
 		return protoreflect.Value{}, out, errDecode
 	}
-	list.Append({{.ToValue}})
+	list.Append({{or .ImplToValue .ToValue}})
 	out.n = n
 	return listv, out, nil
 }
@@ -96,6 +96,11 @@ type ProtoKind struct {
 	ToValue   Expr
 	FromValue Expr
 
+	// Conversion to protoreflect.Value in "internal/impl", where the
+	// unmarshalOptions opts decide how values are allocated, if different
+	// from ToValue.
+	ImplToValue Expr
+
 	// Conversions to/from generated structures.
 	GoType         GoType
 	ToGoType       Expr
@@ -234,22 +239,24 @@ var ProtoKinds = []ProtoKind{
 		FromGoType: "math.Float64bits(v)",
 	},
 	{
-		Name:       "String",
-		WireType:   WireBytes,
-		ToValue:    "protoreflect.ValueOfString(string(v))",
-		FromValue:  "v.String()",
-		GoType:     GoString,
-		ToGoType:   "string(v)",
-		FromGoType: "v",
+		Name:        "String",
+		WireType:    WireBytes,
+		ToValue:     "protoreflect.ValueOfString(string(v))",
+		ImplToValue: "protoreflect.ValueOfString(opts.string(v))",
+		FromValue:   "v.String()",
+		GoType:      GoString,
+		ToGoType:    "opts.string(v)",
+		FromGoType:  "v",
 	},
 	{
 		Name:           "Bytes",
 		WireType:       WireBytes,
 		ToValue:        "protoreflect.ValueOfBytes(append(emptyBuf[:], v...))",
+		ImplToValue:    "protoreflect.ValueOfBytes(opts.bytes(v))",
 		FromValue:      "v.Bytes()",
 		GoType:         GoBytes,
-		ToGoType:       "append(emptyBuf[:], v...)",
-		ToGoTypeNoZero: "append(([]byte)(nil), v...)",
+		ToGoType:       "opts.bytes(v)",
+		ToGoTypeNoZero: "opts.bytesNoZero(v)",
 		FromGoType:     "v",
 		NoPointer:      true,
 	},
@@ -48,11 +48,21 @@ type Decoder struct {
 	orig []byte
 	// in contains the unconsumed input.
 	in []byte
+
+	// line and column are the position of the start of orig.
+	line, column int
 }
 
 // NewDecoder returns a Decoder to read the given []byte.
 func NewDecoder(b []byte) *Decoder {
-	return &Decoder{orig: b, in: b}
+	return NewDecoderAt(b, 1, 1)
+}
+
+// NewDecoderAt returns a Decoder to read the given []byte, which starts
+// at the given line and column of a larger input. Positions are reported
+// relative to that input.
+func NewDecoderAt(b []byte, line, column int) *Decoder {
+	return &Decoder{orig: b, in: b, line: line, column: column}
 }
 
 // Peek looks ahead and returns the next token kind without advancing a read.
@@ -151,6 +161,36 @@ func (d *Decoder) Read() (Token, error) {
 	return tok, nil
 }
 
+// ReadValue reads the next JSON value, including all the values nested in it
+// if it is an object or an array, and returns its bytes in the input.
+func (d *Decoder) ReadValue() ([]byte, error) {
+	tok, err := d.Read()
+	if err != nil {
+		return nil, err
+	}
+	start, end := tok.pos, tok.pos+len(tok.raw)
+	switch tok.kind {
+	case ObjectOpen, ArrayOpen:
+		for depth := 1; depth > 0; {
+			tok, err = d.Read()
+			if err != nil {
+				return nil, err
+			}
+			switch tok.kind {
+			case ObjectOpen, ArrayOpen:
+				depth++
+			case ObjectClose, ArrayClose:
+				depth--
+			}
+		}
+		end = tok.pos + len(tok.raw)
+	case Null, Bool, Number, String:
+	default:
+		return nil, d.newSyntaxError(tok.pos, unexpectedFmt, tok.RawString())
+	}
+	return d.orig[start:end], nil
+}
+
 // Any sequence that looks like a non-delimiter (for error reporting).
 var errRegexp = regexp.MustCompile(`^([-+._a-zA-Z0-9]{1,32}|.)`)
 
@@ -224,11 +264,13 @@ func (d *Decoder) newSyntaxError(pos int, f string, x ...interface{}) error {
 // It will panic if index is out of range.
 func (d *Decoder) Position(idx int) (line int, column int) {
 	b := d.orig[:idx]
-	line = bytes.Count(b, []byte("\n")) + 1
+	line = d.line + bytes.Count(b, []byte("\n"))
+	column = d.column
 	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
 		b = b[i+1:]
+		column = 1
 	}
-	column = utf8.RuneCount(b) + 1 // ignore multi-rune characters
+	column += utf8.RuneCount(b) // ignore multi-rune characters
 	return line, column
 }
 
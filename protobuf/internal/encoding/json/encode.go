@@ -5,6 +5,7 @@
 package json
 
 import (
+	"io"
 	"math"
 	"math/bits"
 	"strconv"
@@ -35,8 +36,16 @@ type Encoder struct {
 	lastKind kind
 	indents  []byte
 	out      []byte
+
+	// w, if not nil, receives the output whenever it grows beyond flushSize.
+	w   io.Writer
+	err error // first error returned by w
 }
 
+// flushSize is the size of the output beyond which it is written to the
+// writer of the Encoder, if any.
+const flushSize = 16 << 10
+
 // NewEncoder returns an Encoder.
 //
 // If indent is a non-empty string, it causes every entry for an Array or Object
@@ -55,10 +64,29 @@ func NewEncoder(buf []byte, indent string) (*Encoder, error) {
 }
 
 // Bytes returns the content of the written bytes.
+// If the Encoder has a writer, it only holds the bytes not yet flushed to it.
 func (e *Encoder) Bytes() []byte {
 	return e.out
 }
 
+// SetWriter sets w as the destination of the output. The output is then
+// written to w in chunks as it is produced, and finally by Flush.
+func (e *Encoder) SetWriter(w io.Writer) {
+	e.w = w
+}
+
+// Flush writes the output not yet written to the writer of the Encoder.
+// It returns the first error returned by the writer, if any.
+func (e *Encoder) Flush() error {
+	if e.w != nil && len(e.out) > 0 {
+		if e.err == nil {
+			_, e.err = e.w.Write(e.out)
+		}
+		e.out = e.out[:0]
+	}
+	return e.err
+}
+
 // WriteNull writes out the null value.
 func (e *Encoder) WriteNull() {
 	e.prepareNext(scalar)
@@ -187,6 +215,13 @@ func (e *Encoder) WriteUint(n uint64) {
 	e.out = strconv.AppendUint(e.out, n, 10)
 }
 
+// WriteNumber writes out the given JSON number literal, such as the raw string
+// of a Number token. It does not check that s is a valid JSON number.
+func (e *Encoder) WriteNumber(s string) {
+	e.prepareNext(scalar)
+	e.out = append(e.out, s...)
+}
+
 // StartObject writes out the '{' symbol.
 func (e *Encoder) StartObject() {
 	e.prepareNext(objectOpen)
@@ -226,6 +261,9 @@ func (e *Encoder) EndArray() {
 // prepareNext adds possible comma and indentation for the next value based
 // on last type and indent option. It also updates lastKind to next.
 func (e *Encoder) prepareNext(next kind) {
+	if e.w != nil && len(e.out) >= flushSize {
+		e.Flush()
+	}
 	defer func() {
 		// Set lastKind to next.
 		e.lastKind = next
@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package fieldmask organizes the paths of a google.protobuf.FieldMask
+// as a tree of field names.
+package fieldmask
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+)
+
+// Tree is a set of field mask paths. Each key is the name of a field of
+// a message, and its value holds the paths within that field. A nil value
+// selects the field in its entirety.
+type Tree map[string]Tree
+
+// New returns the tree of paths. A path which is a prefix of another
+// selects the field in its entirety.
+func New(paths []string) Tree {
+	t := Tree{}
+	for _, path := range paths {
+		t.add(path)
+	}
+	return t
+}
+
+func (t Tree) add(path string) {
+	for {
+		name, rest := path, ""
+		if i := strings.IndexByte(path, '.'); i >= 0 {
+			name, rest = path[:i], path[i+1:]
+		}
+		sub, ok := t[name]
+		switch {
+		case rest == "":
+			t[name] = nil
+			return
+		case ok && sub == nil:
+			return // the field is already selected in its entirety
+		case !ok:
+			sub = Tree{}
+			t[name] = sub
+		}
+		t, path = sub, rest
+	}
+}
+
+// Field returns the paths within the field fd, and reports whether
+// any part of the field is selected. The name of a group in a path is
+// the name of its message, and extension fields are never selected.
+func (t Tree) Field(fd protoreflect.FieldDescriptor) (Tree, bool) {
+	if fd.IsExtension() {
+		return nil, false
+	}
+	name := string(fd.Name())
+	if fd.Kind() == protoreflect.GroupKind {
+		name = string(fd.Message().Name())
+	}
+	sub, ok := t[name]
+	return sub, ok
+}
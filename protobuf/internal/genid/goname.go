@@ -14,6 +14,8 @@ const (
 	WeakFields_goname  = "weakFields"
 	WeakFieldsA_goname = "XXX_weak"
 
+	LazyFields_goname = "lazyFields"
+
 	UnknownFields_goname  = "unknownFields"
 	UnknownFieldsA_goname = "XXX_unrecognized"
 
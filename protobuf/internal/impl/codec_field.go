@@ -59,7 +59,7 @@ func (mi *MessageInfo) initOneofFieldCoders(od protoreflect.OneofDescriptor, si
 			if !vi.IsNil() && !vi.Elem().IsNil() && vi.Elem().Elem().Type() == ot {
 				vw = vi.Elem()
 			} else {
-				vw = reflect.New(ot)
+				vw = opts.newValue(ot)
 			}
 			out, err := cf.funcs.unmarshal(b, pointerOfValue(vw).Apply(zeroOffset), wtyp, &cf, opts)
 			if err != nil {
@@ -247,7 +247,7 @@ func consumeMessageInfo(b []byte, p pointer, wtyp protowire.Type, f *coderFieldI
 		return out, errDecode
 	}
 	if p.Elem().IsNil() {
-		p.SetPointer(pointerOfValue(reflect.New(f.mi.GoReflectType.Elem())))
+		p.SetPointer(opts.newMessage(f.mi))
 	}
 	o, err := f.mi.unmarshalPointer(v, p.Elem(), 0, opts)
 	if err != nil {
@@ -400,7 +400,7 @@ func consumeGroupType(b []byte, p pointer, wtyp protowire.Type, f *coderFieldInf
 		return out, errUnknown
 	}
 	if p.Elem().IsNil() {
-		p.SetPointer(pointerOfValue(reflect.New(f.mi.GoReflectType.Elem())))
+		p.SetPointer(opts.newMessage(f.mi))
 	}
 	return f.mi.unmarshalPointer(b, p.Elem(), f.num, opts)
 }
@@ -498,12 +498,14 @@ func consumeMessageSliceInfo(b []byte, p pointer, wtyp protowire.Type, f *coderF
 	if n < 0 {
 		return out, errDecode
 	}
-	m := reflect.New(f.mi.GoReflectType.Elem()).Interface()
-	mp := pointerOfIface(m)
+	mp := opts.newMessage(f.mi)
 	o, err := f.mi.unmarshalPointer(v, mp, 0, opts)
 	if err != nil {
 		return out, err
 	}
+	if opts.arena != nil && p.IsFullPointerSlice() {
+		opts.growFullSlice(p, f.ft)
+	}
 	p.AppendPointerSlice(mp)
 	out.n = n
 	out.initialized = o.initialized
@@ -812,12 +814,14 @@ func consumeGroupSliceInfo(b []byte, p pointer, wtyp protowire.Type, f *coderFie
 	if wtyp != protowire.StartGroupType {
 		return unmarshalOutput{}, errUnknown
 	}
-	m := reflect.New(f.mi.GoReflectType.Elem()).Interface()
-	mp := pointerOfIface(m)
+	mp := opts.newMessage(f.mi)
 	out, err := f.mi.unmarshalPointer(b, mp, f.num, opts)
 	if err != nil {
 		return out, err
 	}
+	if opts.arena != nil && p.IsFullPointerSlice() {
+		opts.growFullSlice(p, f.ft)
+	}
 	p.AppendPointerSlice(mp)
 	return out, nil
 }
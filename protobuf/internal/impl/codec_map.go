@@ -178,7 +178,7 @@ func consumeMapOfMessage(b []byte, mapv reflect.Value, wtyp protowire.Type, mapi
 	}
 	var (
 		key = mapi.keyZero
-		val = reflect.New(f.mi.GoReflectType.Elem())
+		val = opts.newValue(f.mi.GoReflectType.Elem())
 	)
 	for len(b) > 0 {
 		num, wtyp, n := protowire.ConsumeTag(b)
@@ -29,6 +29,7 @@ type coderMessageInfo struct {
 	unknownOffset      offset
 	unknownPtrKind     bool
 	extensionOffset    offset
+	lazyOffset         offset
 	needsInitCheck     bool
 	isMessageSet       bool
 	numRequiredFields  uint8
@@ -45,12 +46,14 @@ type coderFieldInfo struct {
 	tagsize    int                      // size of the varint-encoded tag
 	isPointer  bool                     // true if IsNil may be called on the struct field
 	isRequired bool                     // true if field is required
+	isLazy     bool                     // true if field is unmarshaled lazily
 }
 
 func (mi *MessageInfo) makeCoderMethods(t reflect.Type, si structInfo) {
 	mi.sizecacheOffset = invalidOffset
 	mi.unknownOffset = invalidOffset
 	mi.extensionOffset = invalidOffset
+	mi.lazyOffset = invalidOffset
 
 	if si.sizecacheOffset.IsValid() && si.sizecacheType == sizecacheType {
 		mi.sizecacheOffset = si.sizecacheOffset
@@ -62,6 +65,9 @@ func (mi *MessageInfo) makeCoderMethods(t reflect.Type, si structInfo) {
 	if si.extensionOffset.IsValid() && si.extensionType == extensionFieldsType {
 		mi.extensionOffset = si.extensionOffset
 	}
+	if si.lazyOffset.IsValid() {
+		mi.lazyOffset = si.lazyOffset
+	}
 
 	mi.coderFields = make(map[protowire.Number]*coderFieldInfo)
 	fields := mi.Desc.Fields()
@@ -127,6 +133,7 @@ func (mi *MessageInfo) makeCoderMethods(t reflect.Type, si structInfo) {
 			validation: newFieldValidationInfo(mi, si, fd, ft),
 			isPointer:  fd.Cardinality() == protoreflect.Repeated || fd.HasPresence(),
 			isRequired: fd.Cardinality() == protoreflect.Required,
+			isLazy:     childMessage != nil && isLazyField(fd, si),
 		}
 		mi.orderedCoderFields = append(mi.orderedCoderFields, cf)
 		mi.coderFields[cf.num] = cf
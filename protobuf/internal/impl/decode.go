@@ -6,10 +6,13 @@ package impl
 
 import (
 	"math/bits"
+	"reflect"
 
 	"github.com/golang/protobuf/protobuf/encoding/protowire"
+	"github.com/golang/protobuf/protobuf/internal/arena"
 	"github.com/golang/protobuf/protobuf/internal/errors"
 	"github.com/golang/protobuf/protobuf/internal/flags"
+	"github.com/golang/protobuf/protobuf/internal/strs"
 	"github.com/golang/protobuf/protobuf/proto"
 	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
 	"github.com/golang/protobuf/protobuf/reflect/protoregistry"
@@ -26,6 +29,7 @@ type unmarshalOptions struct {
 		FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error)
 	}
 	depth int
+	arena *arena.Arena
 }
 
 func (o unmarshalOptions) Options() proto.UnmarshalOptions {
@@ -50,6 +54,88 @@ var lazyUnmarshalOptions = unmarshalOptions{
 	depth:    protowire.DefaultRecursionLimit,
 }
 
+// newValue returns a pointer to a new zero value of type t,
+// allocated from the arena if there is one.
+func (o unmarshalOptions) newValue(t reflect.Type) reflect.Value {
+	if o.arena != nil {
+		return arenaNew(o.arena, t).AsValueOf(t)
+	}
+	return reflect.New(t)
+}
+
+// newPointer is like newValue, but returns a pointer.
+func (o unmarshalOptions) newPointer(t reflect.Type) pointer {
+	if o.arena != nil {
+		return arenaNew(o.arena, t)
+	}
+	return pointerOfValue(reflect.New(t))
+}
+
+// newMessage returns a new message of the type of mi.
+func (o unmarshalOptions) newMessage(mi *MessageInfo) pointer {
+	return o.newPointer(mi.GoReflectType.Elem())
+}
+
+// string returns v as a string.
+func (o unmarshalOptions) string(v []byte) string {
+	if o.flags&protoiface.UnmarshalAliasStrings != 0 {
+		// The caller has promised not to modify the input.
+		return strs.UnsafeString(v)
+	}
+	if o.arena != nil {
+		// The copy is never modified, so it can back the string.
+		return strs.UnsafeString(o.arena.Bytes(v))
+	}
+	return string(v)
+}
+
+// bytes returns a copy of v, which is non-nil even if v is empty.
+// It returns v itself if bytes fields may alias the input.
+func (o unmarshalOptions) bytes(v []byte) []byte {
+	if o.flags&protoiface.UnmarshalAliasBytes != 0 && len(v) > 0 {
+		return v[:len(v):len(v)]
+	}
+	if o.arena != nil && len(v) > 0 {
+		return o.arena.Bytes(v)
+	}
+	return append(emptyBuf[:], v...)
+}
+
+// bytesNoZero returns a copy of v, which is nil if v is empty.
+// It returns v itself if bytes fields may alias the input.
+func (o unmarshalOptions) bytesNoZero(v []byte) []byte {
+	if o.flags&protoiface.UnmarshalAliasBytes != 0 && len(v) > 0 {
+		return v[:len(v):len(v)]
+	}
+	if o.arena != nil && len(v) > 0 {
+		return o.arena.Bytes(v)
+	}
+	return append(([]byte)(nil), v...)
+}
+
+// growSlice grows the capacity of the slice of type t at p by n elements,
+// using memory allocated from the arena.
+func (o unmarshalOptions) growSlice(p pointer, t reflect.Type, n int) {
+	p.arenaGrowSlice(o.arena, t, n)
+}
+
+// growFullSlice doubles the capacity of the slice of type t at p, which is full,
+// so that appending elements to it does not allocate outside the arena.
+func (o unmarshalOptions) growFullSlice(p pointer, t reflect.Type) {
+	n := p.sliceLen(t)
+	if n < 4 {
+		n = 4
+	}
+	p.arenaGrowSlice(o.arena, t, n)
+}
+
+// arenaOf returns the arena given to an Unmarshal method, which is nil
+// if there is none.
+func arenaOf(a protoiface.Arena) *arena.Arena {
+	p, _ := a.(*arena.Arena)
+	return p
+}
+
 type unmarshalOutput struct {
 	n           int // number of bytes consumed
 	initialized bool
@@ -67,6 +153,7 @@ func (mi *MessageInfo) unmarshal(in protoiface.UnmarshalInput) (protoiface.Unmar
 		flags:    in.Flags,
 		resolver: in.Resolver,
 		depth:    in.Depth,
+		arena:    arenaOf(in.Arena),
 	})
 	var flags protoiface.UnmarshalOutputFlags
 	if out.initialized {
@@ -145,7 +232,11 @@ func (mi *MessageInfo) unmarshalPointer(b []byte, p pointer, groupTag protowire.
 				break
 			}
 			var o unmarshalOutput
-			o, err = f.funcs.unmarshal(b, p.Apply(f.offset), wtyp, f, opts)
+			if f.isLazy {
+				o, err = mi.unmarshalLazyField(b, p, wtyp, f, opts)
+			} else {
+				o, err = f.funcs.unmarshal(b, p.Apply(f.offset), wtyp, f, opts)
+			}
 			n = o.n
 			if err != nil {
 				break
@@ -64,6 +64,9 @@ func (mi *MessageInfo) sizePointerSlow(p pointer, opts marshalOptions) (size int
 		}
 		return size
 	}
+	if mi.lazyOffset.IsValid() {
+		mi.lazyInitFields(p)
+	}
 	if mi.extensionOffset.IsValid() {
 		e := p.Apply(mi.extensionOffset).Extensions()
 		size += mi.sizeExtensions(e, opts)
@@ -118,6 +121,9 @@ func (mi *MessageInfo) marshalAppendPointer(b []byte, p pointer, opts marshalOpt
 	if flags.ProtoLegacy && mi.isMessageSet {
 		return marshalMessageSet(mi, b, p, opts)
 	}
+	if mi.lazyOffset.IsValid() {
+		mi.lazyInitFields(p)
+	}
 	var err error
 	// The old marshaler encodes extensions at beginning.
 	if mi.extensionOffset.IsValid() {
@@ -0,0 +1,258 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package impl
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/golang/protobuf/protobuf/encoding/protowire"
+	"github.com/golang/protobuf/protobuf/internal/errors"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+)
+
+// lazyFields holds the wire encoding of message fields which have been
+// unmarshaled lazily and not yet accessed.
+//
+// Generated messages with fields annotated with [lazy = true] have a
+// field of type LazyFields, which is allocated by the unmarshaler.
+// A field is decoded into its struct field upon first access through
+// a generated getter, protobuf reflection, or a fast-path method.
+// Until then, the struct field is nil.
+//
+// Marshaling or merging a message decodes all of its lazy fields first.
+// Emitting the retained encoding instead would let a concurrent reader
+// change the size of a message between Size and Marshal.
+type lazyFields struct {
+	pending uint32 // atomically loaded; number of entries in fields
+	mu      sync.Mutex
+	fields  map[protowire.Number]*lazyField
+}
+
+type lazyField struct {
+	b    []byte // tag and value of every occurrence of the field
+	opts unmarshalOptions
+}
+
+// isLazyField reports whether fd is unmarshaled lazily.
+//
+// Only singular, optional message fields outside of a oneof are lazy,
+// since their unset value is a nil pointer which cannot be confused
+// with a decoded value. Messages lacking a LazyFields struct field,
+// such as those generated with exported struct fields, are always
+// unmarshaled eagerly.
+func isLazyField(fd protoreflect.FieldDescriptor, si structInfo) bool {
+	if !si.lazyOffset.IsValid() {
+		return false
+	}
+	if fd, ok := fd.(interface{ IsLazy() bool }); !ok || !fd.IsLazy() {
+		return false
+	}
+	return fd.Kind() == protoreflect.MessageKind &&
+		fd.Cardinality() == protoreflect.Optional &&
+		fd.ContainingOneof() == nil &&
+		!fd.IsWeak()
+}
+
+// unmarshalLazyField unmarshals a lazy field of the message p.
+//
+// The value is validated, but only decoded if validation cannot ensure that
+// decoding it later succeeds, so that the errors reported by Unmarshal and
+// the required fields checked by it are the same as for eager unmarshaling.
+func (mi *MessageInfo) unmarshalLazyField(b []byte, p pointer, wtyp protowire.Type, f *coderFieldInfo, opts unmarshalOptions) (out unmarshalOutput, err error) {
+	fp := p.Apply(f.offset)
+	if wtyp != protowire.BytesType || opts.depth <= 0 || !fp.Elem().IsNil() {
+		return f.funcs.unmarshal(b, fp, wtyp, f, opts)
+	}
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return out, errDecode
+	}
+	out, valid := f.mi.validate(v, 0, opts)
+	if valid != ValidationValid || !out.initialized {
+		return f.funcs.unmarshal(b, fp, wtyp, f, opts)
+	}
+	lp := p.Apply(mi.lazyOffset).LazyFields()
+	if *lp == nil {
+		*lp = &lazyFields{}
+	}
+	(*lp).append(f.num, b[:n], opts)
+	out.n = n
+	return out, nil
+}
+
+func (lf *lazyFields) append(num protowire.Number, b []byte, opts unmarshalOptions) {
+	if lf.fields == nil {
+		lf.fields = make(map[protowire.Number]*lazyField)
+	}
+	e := lf.fields[num]
+	if e == nil {
+		e = &lazyField{}
+		lf.fields[num] = e
+		atomic.AddUint32(&lf.pending, 1)
+	}
+	e.b = protowire.AppendTag(e.b, num, protowire.BytesType)
+	e.b = append(e.b, b...)
+	// The field may be decoded concurrently or after the arena is reset.
+	opts.arena = nil
+	e.opts = opts
+}
+
+// lazyFieldsOf returns the lazy fields of the message p, or nil if none of
+// its fields are waiting to be decoded.
+func (mi *MessageInfo) lazyFieldsOf(p pointer) *lazyFields {
+	if p.IsNil() || !mi.lazyOffset.IsValid() {
+		return nil
+	}
+	lf := *p.Apply(mi.lazyOffset).LazyFields()
+	if lf == nil || atomic.LoadUint32(&lf.pending) == 0 {
+		return nil
+	}
+	return lf
+}
+
+// lazyInitField decodes the field num of the message p if it has been
+// unmarshaled lazily. It may be called concurrently.
+func (mi *MessageInfo) lazyInitField(p pointer, num protowire.Number) {
+	lf := mi.lazyFieldsOf(p)
+	if lf == nil {
+		return
+	}
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	mi.lazyInitFieldLocked(p, lf, num)
+}
+
+// lazyInitFields decodes all fields of the message p which have been
+// unmarshaled lazily. It may be called concurrently.
+func (mi *MessageInfo) lazyInitFields(p pointer) {
+	lf := mi.lazyFieldsOf(p)
+	if lf == nil {
+		return
+	}
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	for num := range lf.fields {
+		mi.lazyInitFieldLocked(p, lf, num)
+	}
+}
+
+func (mi *MessageInfo) lazyInitFieldLocked(p pointer, lf *lazyFields, num protowire.Number) {
+	e, ok := lf.fields[num]
+	if !ok {
+		return
+	}
+	f := mi.coderFields[num]
+	b := e.b
+	for len(b) > 0 {
+		_, wtyp, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			panic(errors.New("bad tag in lazy field decoding"))
+		}
+		b = b[n:]
+		out, err := f.funcs.unmarshal(b, p.Apply(f.offset), wtyp, f, e.opts)
+		if err != nil {
+			panic(errors.New("decode failure in lazy field decoding: %v", err))
+		}
+		b = b[out.n:]
+	}
+	// The decoded value must be stored before the pending count is
+	// decremented, since readers skip locking once it reaches zero.
+	delete(lf.fields, num)
+	atomic.AddUint32(&lf.pending, ^uint32(0))
+}
+
+// clearLazyField discards the encoded value of the field num of the message p,
+// which is about to be overwritten. It must not be called concurrently.
+func (mi *MessageInfo) clearLazyField(p pointer, num protowire.Number) {
+	lf := mi.lazyFieldsOf(p)
+	if lf == nil {
+		return
+	}
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	if _, ok := lf.fields[num]; ok {
+		delete(lf.fields, num)
+		atomic.AddUint32(&lf.pending, ^uint32(0))
+	}
+}
+
+// fieldInfoForLazyMessage wraps the reflection functions of a lazy field
+// so that its value is decoded before it is accessed.
+func (mi *MessageInfo) fieldInfoForLazyMessage(fi fieldInfo) fieldInfo {
+	num := fi.fieldDesc.Number()
+	has, clear, get, set, mutable := fi.has, fi.clear, fi.get, fi.set, fi.mutable
+	fi.has = func(p pointer) bool {
+		mi.lazyInitField(p, num)
+		return has(p)
+	}
+	fi.clear = func(p pointer) {
+		mi.clearLazyField(p, num)
+		clear(p)
+	}
+	fi.get = func(p pointer) protoreflect.Value {
+		mi.lazyInitField(p, num)
+		return get(p)
+	}
+	fi.set = func(p pointer, v protoreflect.Value) {
+		mi.clearLazyField(p, num)
+		set(p, v)
+	}
+	fi.mutable = func(p pointer) protoreflect.Value {
+		mi.lazyInitField(p, num)
+		return mutable(p)
+	}
+	return fi
+}
+
+// lazyMessageOf returns the MessageInfo and pointer of the generated message m.
+func lazyMessageOf(m interface{}) (*MessageInfo, pointer, bool) {
+	switch m := m.(protoreflect.ProtoMessage).ProtoReflect().(type) {
+	case *messageState:
+		return m.messageInfo(), m.pointer(), true
+	case *messageReflectWrapper:
+		return m.messageInfo(), m.pointer(), true
+	}
+	return nil, pointer{}, false
+}
+
+// UnmarshalField decodes the field num of the message m if it has been
+// unmarshaled lazily. Generated getters of lazy fields call it before
+// reading the struct field. It may be called concurrently.
+func (Export) UnmarshalField(m interface{}, num protoreflect.FieldNumber) {
+	if mi, p, ok := lazyMessageOf(m); ok {
+		mi.lazyInitField(p, num)
+	}
+}
+
+// ClearLazyField discards the encoded value of the field num of the message m.
+// Generated setters of lazy fields call it before writing the struct field.
+func (Export) ClearLazyField(m interface{}, num protoreflect.FieldNumber) {
+	if mi, p, ok := lazyMessageOf(m); ok {
+		mi.clearLazyField(p, num)
+	}
+}
+
+// IsLazyField reports whether the field fd of m is unmarshaled lazily
+// and has not been decoded yet.
+// It is exported for testing.
+func IsLazyField(m protoreflect.Message, fd protoreflect.FieldDescriptor) bool {
+	mi, ok := m.Type().(*MessageInfo)
+	if !ok {
+		return false
+	}
+	p, ok := mi.getPointer(m)
+	if !ok {
+		return false
+	}
+	lf := mi.lazyFieldsOf(p)
+	if lf == nil {
+		return false
+	}
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	_, ok = lf.fields[fd.Number()]
+	return ok
+}
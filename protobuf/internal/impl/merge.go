@@ -41,6 +41,10 @@ func (mi *MessageInfo) mergePointer(dst, src pointer, opts mergeOptions) {
 	if src.IsNil() {
 		return
 	}
+	if mi.lazyOffset.IsValid() {
+		mi.lazyInitFields(dst)
+		mi.lazyInitFields(src)
+	}
 	for _, f := range mi.orderedCoderFields {
 		if f.funcs.merge == nil {
 			continue
@@ -109,6 +109,7 @@ func (mi *MessageInfo) getPointer(m protoreflect.Message) (p pointer, ok bool) {
 type (
 	SizeCache       = int32
 	WeakFields      = map[int32]protoreflect.ProtoMessage
+	LazyFields      = *lazyFields
 	UnknownFields   = unknownFieldsA // TODO: switch to unknownFieldsB
 	unknownFieldsA  = []byte
 	unknownFieldsB  = *[]byte
@@ -118,6 +119,7 @@ type (
 var (
 	sizecacheType       = reflect.TypeOf(SizeCache(0))
 	weakFieldsType      = reflect.TypeOf(WeakFields(nil))
+	lazyFieldsType      = reflect.TypeOf(LazyFields(nil))
 	unknownFieldsAType  = reflect.TypeOf(unknownFieldsA(nil))
 	unknownFieldsBType  = reflect.TypeOf(unknownFieldsB(nil))
 	extensionFieldsType = reflect.TypeOf(ExtensionFields(nil))
@@ -128,6 +130,7 @@ type structInfo struct {
 	sizecacheType   reflect.Type
 	weakOffset      offset
 	weakType        reflect.Type
+	lazyOffset      offset
 	unknownOffset   offset
 	unknownType     reflect.Type
 	extensionOffset offset
@@ -143,6 +146,7 @@ func (mi *MessageInfo) makeStructInfo(t reflect.Type) structInfo {
 	si := structInfo{
 		sizecacheOffset: invalidOffset,
 		weakOffset:      invalidOffset,
+		lazyOffset:      invalidOffset,
 		unknownOffset:   invalidOffset,
 		extensionOffset: invalidOffset,
 
@@ -165,6 +169,10 @@ fieldLoop:
 				si.weakOffset = offsetOf(f, mi.Exporter)
 				si.weakType = f.Type
 			}
+		case genid.LazyFields_goname:
+			if f.Type == lazyFieldsType {
+				si.lazyOffset = offsetOf(f, mi.Exporter)
+			}
 		case genid.UnknownFields_goname, genid.UnknownFieldsA_goname:
 			if f.Type == unknownFieldsAType || f.Type == unknownFieldsBType {
 				si.unknownOffset = offsetOf(f, mi.Exporter)
@@ -176,6 +184,10 @@ fieldLoop:
 				si.extensionType = f.Type
 			}
 		default:
+			// Fields are identified by their struct tags rather than their
+			// names, so that the unexported fields of messages generated with
+			// the opaque API are found as well. Accessing them requires an
+			// Exporter in a purego environment.
 			for _, s := range strings.Split(f.Tag.Get("protobuf"), ",") {
 				if len(s) > 0 && strings.Trim(s, "0123456789") == "" {
 					n, _ := strconv.ParseUint(s, 10, 64)
@@ -76,6 +76,9 @@ func (mi *MessageInfo) makeKnownFieldsFunc(si structInfo) {
 			fi = fieldInfoForWeakMessage(fd, si.weakOffset)
 		case fd.Message() != nil:
 			fi = fieldInfoForMessage(fd, fs, mi.Exporter)
+			if isLazyField(fd, si) {
+				fi = mi.fieldInfoForLazyMessage(fi)
+			}
 		default:
 			fi = fieldInfoForScalar(fd, fs, mi.Exporter)
 		}
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+
+	"github.com/golang/protobuf/protobuf/internal/arena"
 )
 
 const UnsafeEnabled = false
@@ -125,6 +127,7 @@ func (p pointer) Bytes() *[]byte           { return p.v.Interface().(*[]byte) }
 func (p pointer) BytesPtr() **[]byte       { return p.v.Interface().(**[]byte) }
 func (p pointer) BytesSlice() *[][]byte    { return p.v.Interface().(*[][]byte) }
 func (p pointer) WeakFields() *weakFields  { return (*weakFields)(p.v.Interface().(*WeakFields)) }
+func (p pointer) LazyFields() *LazyFields  { return p.v.Interface().(*LazyFields) }
 func (p pointer) Extensions() *map[int32]ExtensionField {
 	return p.v.Interface().(*map[int32]ExtensionField)
 }
@@ -154,6 +157,31 @@ func (p pointer) AppendPointerSlice(v pointer) {
 	sp.Set(reflect.Append(sp, v.v))
 }
 
+// arenaNew returns a pointer to a new zero value of type t.
+// Without package unsafe, the arena only allocates bytes.
+func arenaNew(a *arena.Arena, t reflect.Type) pointer {
+	return pointer{v: reflect.New(t)}
+}
+
+// arenaGrowSlice grows the capacity of the slice of type t at p by n elements.
+// Without package unsafe, the arena only allocates bytes.
+func (p pointer) arenaGrowSlice(a *arena.Arena, t reflect.Type, n int) {
+	sp := p.v.Elem()
+	sp.Set(reflect.AppendSlice(reflect.MakeSlice(t, 0, sp.Len()+n), sp))
+}
+
+// sliceLen returns the length of the slice of type t at p.
+func (p pointer) sliceLen(t reflect.Type) int {
+	return p.v.Elem().Len()
+}
+
+// IsFullPointerSlice reports whether appending to p, which must be a []*T,
+// reallocates it.
+func (p pointer) IsFullPointerSlice() bool {
+	s := p.v.Elem()
+	return s.Len() == s.Cap()
+}
+
 // SetPointer sets *p to v.
 func (p pointer) SetPointer(v pointer) {
 	p.v.Elem().Set(v.v)
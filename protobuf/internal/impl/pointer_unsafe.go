@@ -11,6 +11,8 @@ import (
 	"reflect"
 	"sync/atomic"
 	"unsafe"
+
+	"github.com/golang/protobuf/protobuf/internal/arena"
 )
 
 const UnsafeEnabled = true
@@ -113,6 +115,7 @@ func (p pointer) Bytes() *[]byte                        { return (*[]byte)(p.p)
 func (p pointer) BytesPtr() **[]byte                    { return (**[]byte)(p.p) }
 func (p pointer) BytesSlice() *[][]byte                 { return (*[][]byte)(p.p) }
 func (p pointer) WeakFields() *weakFields               { return (*weakFields)(p.p) }
+func (p pointer) LazyFields() *LazyFields               { return (*LazyFields)(p.p) }
 func (p pointer) Extensions() *map[int32]ExtensionField { return (*map[int32]ExtensionField)(p.p) }
 
 func (p pointer) Elem() pointer {
@@ -133,6 +136,29 @@ func (p pointer) AppendPointerSlice(v pointer) {
 	*(*[]pointer)(p.p) = append(*(*[]pointer)(p.p), v)
 }
 
+// arenaNew returns a pointer to a new zero value of type t, allocated from a.
+func arenaNew(a *arena.Arena, t reflect.Type) pointer {
+	return pointer{p: a.New(t)}
+}
+
+// arenaGrowSlice grows the capacity of the slice of type t at p by n elements,
+// using memory allocated from a.
+func (p pointer) arenaGrowSlice(a *arena.Arena, t reflect.Type, n int) {
+	a.GrowSlice(t, p.p, n)
+}
+
+// sliceLen returns the length of the slice of type t at p.
+func (p pointer) sliceLen(t reflect.Type) int {
+	return len(*(*[]struct{})(p.p))
+}
+
+// IsFullPointerSlice reports whether appending to p, which must be a []*T,
+// reallocates it.
+func (p pointer) IsFullPointerSlice() bool {
+	s := *(*[]pointer)(p.p)
+	return len(s) == cap(s)
+}
+
 // SetPointer sets *p to v.
 func (p pointer) SetPointer(v pointer) {
 	*(*unsafe.Pointer)(p.p) = (unsafe.Pointer)(v.p)
@@ -29,7 +29,8 @@ const (
 	// The validator was unable to render a judgement.
 	//
 	// The only causes of this status are an aberrant message type appearing somewhere
-	// in the message or a failure in the extension resolver.
+	// in the message, a failure in the extension resolver, or messages nested
+	// more deeply than the recursion limit allows.
 	ValidationUnknown ValidationStatus = iota + 1
 
 	// ValidationInvalid indicates that unmarshaling the message will fail.
@@ -247,6 +248,10 @@ func (mi *MessageInfo) validate(b []byte, groupTag protowire.Number, opts unmars
 	start := len(b)
 State:
 	for len(states) > 0 {
+		if opts.depth > 0 && len(states) > opts.depth {
+			// Unmarshaling reports an error when the recursion limit is exceeded.
+			return out, ValidationUnknown
+		}
 		st := &states[len(states)-1]
 		for len(b) > 0 {
 			// Parse the tag (field number and wire type).
@@ -0,0 +1,29 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import "github.com/golang/protobuf/protobuf/internal/arena"
+
+// Arena allocates the values of unmarshaled messages in bulk.
+// It is used by setting UnmarshalOptions.Arena.
+//
+// Sub-messages, strings, bytes and repeated fields are carved out
+// of slabs owned by the arena instead of being allocated one by one,
+// which reduces the load on the garbage collector when decoding many
+// small messages. All values are released together by Reset.
+// In builds without package unsafe, such as with the purego build tag,
+// only strings and bytes are allocated from the arena.
+//
+// The zero value is ready for use. An Arena must not be used concurrently.
+type Arena struct {
+	a arena.Arena
+}
+
+// Reset releases all values allocated by the arena so that their memory
+// can be reused. Messages unmarshaled with the arena, and any values
+// obtained from them, including strings, must not be used afterwards.
+func (a *Arena) Reset() {
+	a.a.Reset()
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/protobuf/encoding/prototext"
+	"github.com/golang/protobuf/protobuf/proto"
+)
+
+func TestDecodeArena(t *testing.T) {
+	arena := &proto.Arena{}
+	decode := func(reverse bool) {
+		for i := range testValidMessages {
+			test := testValidMessages[i]
+			if reverse {
+				test = testValidMessages[len(testValidMessages)-1-i]
+			}
+			for _, want := range test.decodeTo {
+				opts := test.unmarshalOptions
+				opts.AllowPartial = test.partial
+				opts.Arena = arena
+				wire := append(([]byte)(nil), test.wire...)
+				got := reflect.New(reflect.TypeOf(want).Elem()).Interface().(proto.Message)
+				if err := opts.Unmarshal(wire, got); err != nil {
+					t.Errorf("%s (%T): Unmarshal error: %v", test.desc, want, err)
+					continue
+				}
+				// Values allocated from the arena must not alias the input.
+				for i := range wire {
+					wire[i] = 0
+				}
+				if !proto.Equal(got, want) && got.ProtoReflect().IsValid() && want.ProtoReflect().IsValid() {
+					t.Errorf("%s (%T): Unmarshal returned unexpected result; got:\n%v\nwant:\n%v", test.desc, want, prototext.Format(got), prototext.Format(want))
+				}
+			}
+		}
+	}
+	decode(false)
+	arena.Reset()
+	// Decoding in another order reuses the memory of the arena for
+	// other values, which must start out empty.
+	decode(true)
+}
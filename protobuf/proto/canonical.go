@@ -0,0 +1,205 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"bytes"
+	"math"
+	"sort"
+
+	"github.com/golang/protobuf/protobuf/encoding/protowire"
+	"github.com/golang/protobuf/protobuf/internal/encoding/messageset"
+	"github.com/golang/protobuf/protobuf/internal/order"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+)
+
+// IsCanonical reports whether b is the canonical encoding of a message
+// of the same type as m, as produced by MarshalOptions.Canonical.
+// The contents of m are ignored. Required fields are not checked.
+func IsCanonical(b []byte, m Message) bool {
+	if m == nil {
+		return len(b) == 0
+	}
+	got := m.ProtoReflect().New()
+	if err := (UnmarshalOptions{AllowPartial: true}).Unmarshal(b, got.Interface()); err != nil {
+		return false
+	}
+	want, err := MarshalOptions{AllowPartial: true, Canonical: true}.marshalMessageCanonical(nil, got)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(b, want)
+}
+
+// unknownField is a field in the unknown fields of a message.
+type unknownField struct {
+	num protowire.Number
+	raw []byte
+}
+
+// splitUnknown splits the unknown fields b into fields, sorted by number.
+func splitUnknown(b protoreflect.RawFields) ([]unknownField, error) {
+	var fields []unknownField
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		m := protowire.ConsumeFieldValue(num, typ, b[n:])
+		if m < 0 {
+			return nil, protowire.ParseError(m)
+		}
+		fields = append(fields, unknownField{num, b[:n+m]})
+		b = b[n+m:]
+	}
+	sort.SliceStable(fields, func(i, j int) bool {
+		return fields[i].num < fields[j].num
+	})
+	return fields, nil
+}
+
+func (o MarshalOptions) marshalMessageCanonical(b []byte, m protoreflect.Message) ([]byte, error) {
+	if messageset.IsMessageSet(m.Descriptor()) {
+		o.Deterministic = true
+		return o.marshalMessageSet(b, m)
+	}
+	unknown, err := splitUnknown(m.GetUnknown())
+	if err != nil {
+		return b, err
+	}
+	order.RangeFields(m, order.NumberFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		for len(unknown) > 0 && unknown[0].num < fd.Number() {
+			b = append(b, unknown[0].raw...)
+			unknown = unknown[1:]
+		}
+		b, err = o.marshalFieldCanonical(b, fd, v)
+		return err == nil
+	})
+	if err != nil {
+		return b, err
+	}
+	for _, f := range unknown {
+		b = append(b, f.raw...)
+	}
+	return b, nil
+}
+
+func (o MarshalOptions) marshalFieldCanonical(b []byte, fd protoreflect.FieldDescriptor, v protoreflect.Value) ([]byte, error) {
+	switch {
+	case fd.IsList():
+		return o.marshalListCanonical(b, fd, v.List())
+	case fd.IsMap():
+		return o.marshalMapCanonical(b, fd, v.Map())
+	}
+	if !fd.HasPresence() && isDefaultValue(fd, v) {
+		return b, nil
+	}
+	return o.marshalSingularCanonical(b, fd, v)
+}
+
+func (o MarshalOptions) marshalSingularCanonical(b []byte, fd protoreflect.FieldDescriptor, v protoreflect.Value) ([]byte, error) {
+	var err error
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+		var pos int
+		b, pos = appendSpeculativeLength(b)
+		b, err = o.marshalMessageCanonical(b, v.Message())
+		if err != nil {
+			return b, err
+		}
+		return finishSpeculativeLength(b, pos), nil
+	case protoreflect.GroupKind:
+		b = protowire.AppendTag(b, fd.Number(), protowire.StartGroupType)
+		b, err = o.marshalMessageCanonical(b, v.Message())
+		if err != nil {
+			return b, err
+		}
+		return protowire.AppendTag(b, fd.Number(), protowire.EndGroupType), nil
+	default:
+		b = protowire.AppendTag(b, fd.Number(), wireTypes[fd.Kind()])
+		return o.marshalSingular(b, fd, v)
+	}
+}
+
+func (o MarshalOptions) marshalListCanonical(b []byte, fd protoreflect.FieldDescriptor, list protoreflect.List) ([]byte, error) {
+	if list.Len() == 0 {
+		return b, nil
+	}
+	switch fd.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.MessageKind, protoreflect.GroupKind:
+		for i, llen := 0, list.Len(); i < llen; i++ {
+			var err error
+			b, err = o.marshalSingularCanonical(b, fd, list.Get(i))
+			if err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	}
+	size := 0
+	for i, llen := 0, list.Len(); i < llen; i++ {
+		size += o.sizeSingular(fd.Number(), fd.Kind(), list.Get(i))
+	}
+	b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+	b = protowire.AppendVarint(b, uint64(size))
+	for i, llen := 0, list.Len(); i < llen; i++ {
+		var err error
+		b, err = o.marshalSingular(b, fd, list.Get(i))
+		if err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+func (o MarshalOptions) marshalMapCanonical(b []byte, fd protoreflect.FieldDescriptor, mapv protoreflect.Map) ([]byte, error) {
+	keyf := fd.MapKey()
+	valf := fd.MapValue()
+	var err error
+	order.RangeEntries(mapv, order.GenericKeyOrder, func(key protoreflect.MapKey, value protoreflect.Value) bool {
+		b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+		var pos int
+		b, pos = appendSpeculativeLength(b)
+
+		b, err = o.marshalSingularCanonical(b, keyf, key.Value())
+		if err != nil {
+			return false
+		}
+		b, err = o.marshalSingularCanonical(b, valf, value)
+		if err != nil {
+			return false
+		}
+		b = finishSpeculativeLength(b, pos)
+		return true
+	})
+	return b, err
+}
+
+// isDefaultValue reports whether v is the default value of the scalar field fd.
+// Floating-point values are compared by their bits, so that -0 is not the
+// default value 0.
+func isDefaultValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+	d := fd.Default()
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return v.Bool() == d.Bool()
+	case protoreflect.EnumKind:
+		return v.Enum() == d.Enum()
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return v.Int() == d.Int()
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return v.Uint() == d.Uint()
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return math.Float64bits(v.Float()) == math.Float64bits(d.Float())
+	case protoreflect.StringKind:
+		return v.String() == d.String()
+	case protoreflect.BytesKind:
+		return bytes.Equal(v.Bytes(), d.Bytes())
+	}
+	return false
+}
@@ -0,0 +1,333 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto_test
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/golang/protobuf/protobuf/encoding/prototext"
+	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/testing/protopack"
+
+	testpb "github.com/golang/protobuf/protobuf/internal/testprotos/test"
+	test3pb "github.com/golang/protobuf/protobuf/internal/testprotos/test3"
+)
+
+// canonicalGolden holds messages and their canonical encoding, as specified
+// by MarshalOptions.Canonical. These vectors are independent of this
+// implementation, and must not change.
+var canonicalGolden = []struct {
+	desc string
+	m    proto.Message
+	want protopack.Message
+}{{
+	desc: "default values are omitted without presence",
+	m: &test3pb.TestAllTypes{
+		SingularInt32:  0,
+		SingularString: "",
+		SingularBool:   true,
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 93, Type: protopack.VarintType}, protopack.Bool(true),
+	},
+}, {
+	desc: "default values are kept with presence",
+	m: &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(0),
+		OptionalString: proto.String(""),
+		DefaultInt32:   proto.Int32(81),
+		DefaultString:  proto.String("hello"),
+		DefaultFloat:   proto.Float32(92),
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(0),
+		protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String(""),
+		protopack.Tag{Number: 81, Type: protopack.VarintType}, protopack.Varint(81),
+		protopack.Tag{Number: 91, Type: protopack.Fixed32Type}, protopack.Float32(92),
+		protopack.Tag{Number: 94, Type: protopack.BytesType}, protopack.String("hello"),
+	},
+}, {
+	desc: "proto3 optional default values are kept",
+	m: &test3pb.TestAllTypes{
+		OptionalInt32: proto.Int32(0),
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(0),
+	},
+}, {
+	desc: "negative zero is not the default value",
+	m: &test3pb.TestAllTypes{
+		SingularDouble: math.Copysign(0, -1),
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 92, Type: protopack.Fixed64Type}, protopack.Float64(math.Copysign(0, -1)),
+	},
+}, {
+	desc: "oneof members with default values are kept",
+	m: &testpb.TestAllTypes{
+		OneofField: &testpb.TestAllTypes_OneofUint32{OneofUint32: 0},
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 111, Type: protopack.VarintType}, protopack.Uvarint(0),
+	},
+}, {
+	desc: "empty messages are kept",
+	m: &testpb.TestAllTypes{
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{},
+		Optionalgroup:         &testpb.TestAllTypes_OptionalGroup{},
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 16, Type: protopack.StartGroupType},
+		protopack.Tag{Number: 16, Type: protopack.EndGroupType},
+		protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{},
+	},
+}, {
+	desc: "repeated scalars are packed",
+	m: &testpb.TestAllTypes{
+		RepeatedInt32:  []int32{1, 0, -1},
+		RepeatedString: []string{"a", ""},
+		RepeatedBool:   []bool{true},
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Varint(1), protopack.Varint(0), protopack.Varint(-1),
+		},
+		protopack.Tag{Number: 43, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Bool(true),
+		},
+		protopack.Tag{Number: 44, Type: protopack.BytesType}, protopack.String("a"),
+		protopack.Tag{Number: 44, Type: protopack.BytesType}, protopack.String(""),
+	},
+}, {
+	desc: "map entries are sorted and complete",
+	m: &testpb.TestAllTypes{
+		MapInt32Int32:   map[int32]int32{2: 0, -1: 3},
+		MapStringString: map[string]string{"b": "", "a": "x", "": ""},
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 56, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(-1),
+			protopack.Tag{Number: 2, Type: protopack.VarintType}, protopack.Varint(3),
+		},
+		protopack.Tag{Number: 56, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(2),
+			protopack.Tag{Number: 2, Type: protopack.VarintType}, protopack.Varint(0),
+		},
+		protopack.Tag{Number: 69, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.BytesType}, protopack.String(""),
+			protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.String(""),
+		},
+		protopack.Tag{Number: 69, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.BytesType}, protopack.String("a"),
+			protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.String("x"),
+		},
+		protopack.Tag{Number: 69, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.BytesType}, protopack.String("b"),
+			protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.String(""),
+		},
+	},
+}, {
+	desc: "extensions and unknown fields are ordered with other fields",
+	m: build(
+		&testpb.TestAllExtensions{},
+		extend(testpb.E_OptionalNestedMessage, &testpb.TestAllExtensions_NestedMessage{
+			A: proto.Int32(1),
+		}),
+		extend(testpb.E_OptionalInt32, int32(5)),
+		unknown(protopack.Message{
+			protopack.Tag{Number: 20, Type: protopack.VarintType}, protopack.Varint(2),
+			protopack.Tag{Number: 2000, Type: protopack.BytesType}, protopack.String("x"),
+			protopack.Tag{Number: 10, Type: protopack.Fixed32Type}, protopack.Uint32(3),
+			protopack.Tag{Number: 20, Type: protopack.VarintType}, protopack.Varint(1),
+		}.Marshal()),
+	),
+	want: protopack.Message{
+		protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(5),
+		protopack.Tag{Number: 10, Type: protopack.Fixed32Type}, protopack.Uint32(3),
+		protopack.Tag{Number: 18, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+		},
+		protopack.Tag{Number: 20, Type: protopack.VarintType}, protopack.Varint(2),
+		protopack.Tag{Number: 20, Type: protopack.VarintType}, protopack.Varint(1),
+		protopack.Tag{Number: 2000, Type: protopack.BytesType}, protopack.String("x"),
+	},
+}, {
+	desc: "sub-messages are canonical",
+	m: &testpb.TestAllTypes{
+		RepeatedNestedMessage: []*testpb.TestAllTypes_NestedMessage{{
+			A: proto.Int32(0),
+			Corecursive: &testpb.TestAllTypes{
+				RepeatedInt64: []int64{1 << 40},
+				OptionalInt32: proto.Int32(7),
+			},
+		}},
+	},
+	want: protopack.Message{
+		protopack.Tag{Number: 48, Type: protopack.BytesType}, protopack.LengthPrefix{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(0),
+			protopack.Tag{Number: 2, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(7),
+				protopack.Tag{Number: 32, Type: protopack.BytesType}, protopack.LengthPrefix{
+					protopack.Varint(1 << 40),
+				},
+			},
+		},
+	},
+}}
+
+func TestCanonicalGolden(t *testing.T) {
+	for _, test := range canonicalGolden {
+		want := test.want.Marshal()
+		got, err := proto.MarshalOptions{Canonical: true}.Marshal(test.m)
+		if err != nil {
+			t.Errorf("%s: Marshal error: %v", test.desc, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: canonical encoding mismatch (-want +got):\n%v", test.desc, cmp.Diff(want, got))
+		}
+		if !proto.IsCanonical(want, test.m) {
+			t.Errorf("%s: IsCanonical(want) = false, want true", test.desc)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	for _, test := range testValidMessages {
+		for _, want := range test.decodeTo {
+			t.Run(fmt.Sprintf("%s (%T)", test.desc, want), func(t *testing.T) {
+				opts := proto.MarshalOptions{
+					AllowPartial: test.partial,
+					Canonical:    true,
+				}
+				wire, err := opts.Marshal(want)
+				if err != nil {
+					t.Fatalf("Marshal error: %v\nMessage:\n%v", err, prototext.Format(want))
+				}
+				if size := opts.Size(want); size != len(wire) {
+					t.Errorf("Size and marshal disagree: Size(m)=%v; len(Marshal(m))=%v", size, len(wire))
+				}
+				if !proto.IsCanonical(wire, want) {
+					t.Errorf("IsCanonical(Marshal(m)) = false, want true\nMessage:\n%v", prototext.Format(want))
+				}
+
+				got := want.ProtoReflect().New().Interface()
+				uopts := proto.UnmarshalOptions{
+					AllowPartial: test.partial,
+				}
+				if err := uopts.Unmarshal(wire, got); err != nil {
+					t.Fatalf("Unmarshal error: %v\nMessage:\n%v", err, prototext.Format(want))
+				}
+				wire2, err := opts.Marshal(got)
+				if err != nil {
+					t.Fatalf("Marshal error: %v", err)
+				}
+				if !bytes.Equal(wire, wire2) {
+					t.Errorf("canonical encoding changed after round-trip:\n%v", cmp.Diff(wire, wire2))
+				}
+			})
+		}
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	for _, test := range []struct {
+		desc string
+		m    proto.Message // defaults to testpb.TestAllTypes
+		wire protopack.Message
+		want bool
+	}{{
+		desc: "empty",
+		want: true,
+	}, {
+		desc: "canonical",
+		wire: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Varint(1), protopack.Varint(2),
+			},
+		},
+		want: true,
+	}, {
+		desc: "fields out of order",
+		wire: protopack.Message{
+			protopack.Tag{Number: 14, Type: protopack.BytesType}, protopack.String("x"),
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+		},
+	}, {
+		desc: "default value with presence",
+		wire: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(0),
+		},
+		want: true,
+	}, {
+		desc: "default value without presence",
+		m:    &test3pb.TestAllTypes{},
+		wire: protopack.Message{
+			protopack.Tag{Number: 81, Type: protopack.VarintType}, protopack.Varint(0),
+		},
+	}, {
+		desc: "repeated scalar not packed",
+		wire: protopack.Message{
+			protopack.Tag{Number: 31, Type: protopack.VarintType}, protopack.Varint(1),
+		},
+	}, {
+		desc: "repeated scalar packed in two fields",
+		wire: protopack.Message{
+			protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{protopack.Varint(1)},
+			protopack.Tag{Number: 31, Type: protopack.BytesType}, protopack.LengthPrefix{protopack.Varint(2)},
+		},
+	}, {
+		desc: "repeated field",
+		wire: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(2),
+		},
+	}, {
+		desc: "denormalized varint",
+		wire: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Denormalized{Count: 1, Value: protopack.Varint(1)},
+		},
+	}, {
+		desc: "map entry without value",
+		wire: protopack.Message{
+			protopack.Tag{Number: 56, Type: protopack.BytesType}, protopack.LengthPrefix{
+				protopack.Tag{Number: 1, Type: protopack.VarintType}, protopack.Varint(1),
+			},
+		},
+	}, {
+		desc: "unknown fields out of order",
+		wire: protopack.Message{
+			protopack.Tag{Number: 2001, Type: protopack.VarintType}, protopack.Varint(1),
+			protopack.Tag{Number: 2000, Type: protopack.VarintType}, protopack.Varint(1),
+		},
+	}, {
+		desc: "unknown fields kept as they are",
+		wire: protopack.Message{
+			protopack.Tag{Number: 2000, Type: protopack.VarintType}, protopack.Denormalized{Count: 1, Value: protopack.Varint(1)},
+			protopack.Tag{Number: 2000, Type: protopack.VarintType}, protopack.Varint(2),
+			protopack.Tag{Number: 2001, Type: protopack.Fixed32Type}, protopack.Uint32(3),
+		},
+		want: true,
+	}, {
+		desc: "invalid",
+		wire: protopack.Message{
+			protopack.Tag{Number: 1, Type: protopack.BytesType},
+		},
+	}} {
+		m := test.m
+		if m == nil {
+			m = &testpb.TestAllTypes{}
+		}
+		if got := proto.IsCanonical(test.wire.Marshal(), m); got != test.want {
+			t.Errorf("%s: IsCanonical() = %v, want %v", test.desc, got, test.want)
+		}
+	}
+}
@@ -47,6 +47,19 @@ type UnmarshalOptions struct {
 	// RecursionLimit limits how deeply messages may be nested.
 	// If zero, a default limit is applied.
 	RecursionLimit int
+
+	// If AliasBytes is set, the values of bytes fields alias b instead of
+	// being copied from it, and likewise for string fields if AliasStrings
+	// is set. The caller must not modify b while the message is in use.
+	// Values decoded through protobuf reflection, such as the fields of
+	// messages without generated code, are still copied.
+	AliasBytes   bool
+	AliasStrings bool
+
+	// Arena, if not nil, allocates the sub-messages, strings, bytes and
+	// repeated fields of the unmarshaled message.
+	// The message must not be used after the arena is reset.
+	Arena *Arena
 }
 
 // Unmarshal parses the wire-format message in b and places the result in m.
@@ -102,6 +115,15 @@ func (o UnmarshalOptions) unmarshal(b []byte, m protoreflect.Message) (out proto
 		if o.DiscardUnknown {
 			in.Flags |= protoiface.UnmarshalDiscardUnknown
 		}
+		if o.AliasBytes {
+			in.Flags |= protoiface.UnmarshalAliasBytes
+		}
+		if o.AliasStrings {
+			in.Flags |= protoiface.UnmarshalAliasStrings
+		}
+		if o.Arena != nil {
+			in.Arena = &o.Arena.a
+		}
 		out, err = methods.Unmarshal(in)
 	} else {
 		o.RecursionLimit--
@@ -135,6 +135,59 @@ func TestDecodeEmptyBytes(t *testing.T) {
 	}
 }
 
+func TestDecodeAlias(t *testing.T) {
+	for _, test := range testValidMessages {
+		for _, want := range test.decodeTo {
+			opts := test.unmarshalOptions
+			opts.AllowPartial = test.partial
+			opts.AliasBytes = true
+			opts.AliasStrings = true
+			got := reflect.New(reflect.TypeOf(want).Elem()).Interface().(proto.Message)
+			if err := opts.Unmarshal(test.wire, got); err != nil {
+				t.Errorf("%s (%T): Unmarshal error: %v", test.desc, want, err)
+				continue
+			}
+			if !proto.Equal(got, want) && got.ProtoReflect().IsValid() && want.ProtoReflect().IsValid() {
+				t.Errorf("%s (%T): Unmarshal returned unexpected result; got:\n%v\nwant:\n%v", test.desc, want, prototext.Format(got), prototext.Format(want))
+			}
+		}
+	}
+
+	wire := protopack.Message{
+		protopack.Tag{15, protopack.BytesType}, protopack.Bytes("optional"),
+		protopack.Tag{45, protopack.BytesType}, protopack.Bytes("repeated"),
+		protopack.Tag{45, protopack.BytesType}, protopack.Bytes(nil),
+		protopack.Tag{70, protopack.BytesType}, protopack.LengthPrefix(protopack.Message{
+			protopack.Tag{1, protopack.BytesType}, protopack.String("key"),
+			protopack.Tag{2, protopack.BytesType}, protopack.Bytes("value"),
+		}),
+	}.Marshal()
+	m := &testpb.TestAllTypes{}
+	if err := (proto.UnmarshalOptions{AliasBytes: true}).Unmarshal(wire, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.RepeatedBytes[1] == nil {
+		t.Errorf("unmarshaling zero-length bytes with AliasBytes: got nil bytes, want non-nil")
+	}
+	for _, b := range [][]byte{m.OptionalBytes, m.RepeatedBytes[0], m.MapStringBytes["key"]} {
+		if cap(b) != len(b) {
+			t.Errorf("aliased bytes %q have capacity %v, want %v", b, cap(b), len(b))
+		}
+	}
+	for i := range wire {
+		wire[i] = 'x'
+	}
+	if got, want := string(m.OptionalBytes), "xxxxxxxx"; got != want {
+		t.Errorf("after modifying the input: OptionalBytes = %q, want %q", got, want)
+	}
+	if got, want := string(m.RepeatedBytes[0]), "xxxxxxxx"; got != want {
+		t.Errorf("after modifying the input: RepeatedBytes[0] = %q, want %q", got, want)
+	}
+	if got, want := string(m.MapStringBytes["key"]), "xxxxx"; got != want {
+		t.Errorf("after modifying the input: MapStringBytes[key] = %q, want %q", got, want)
+	}
+}
+
 func build(m proto.Message, opts ...buildOpt) proto.Message {
 	for _, opt := range opts {
 		opt(m)
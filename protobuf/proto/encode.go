@@ -40,15 +40,56 @@ type MarshalOptions struct {
 	// languages. It is not guaranteed to remain stable over time. It is
 	// unstable across different builds with schema changes due to unknown
 	// fields. Users who need canonical serialization (e.g., persistent
-	// storage in a canonical form, fingerprinting, etc.) must define
-	// their own canonicalization specification and implement their own
-	// serializer rather than relying on this API.
+	// storage in a canonical form, fingerprinting, etc.) should use
+	// the Canonical option instead.
 	//
 	// If deterministic serialization is requested, map entries will be
 	// sorted by keys in lexographical order. This is an implementation
 	// detail and subject to change.
 	Deterministic bool
 
+	// Canonical specifies that messages are serialized to their canonical
+	// encoding, which depends only on the contents of the message and may
+	// be reproduced by an implementation in any language:
+	//
+	//   - All fields, known and unknown, appear in increasing order of
+	//     field number. Extension fields are ordered with the other fields.
+	//     Unknown fields with the same number keep their relative order,
+	//     and follow any known field with that number.
+	//   - Fields without presence are not encoded if they are set to their
+	//     default value. Fields with presence, such as optional, required,
+	//     oneof and extension fields, are always encoded if they are set,
+	//     even to their default value. So are messages and groups, even if
+	//     they are empty.
+	//   - Repeated fields of scalar numeric types are always encoded as
+	//     a single packed field, regardless of the [packed] option.
+	//   - Map entries appear in increasing order of their key, as defined
+	//     by the ordering of bools, integers or strings (in bytes) in Go.
+	//     Each entry holds the key followed by the value, both of which
+	//     are always encoded.
+	//   - Varints, including lengths, are encoded in the fewest bytes.
+	//   - Sub-messages and groups are recursively canonical.
+	//   - Unknown fields are kept as they are, with their wire types and
+	//     encoded values unchanged, since a newer schema may know them.
+	//     They are only sorted as described above.
+	//
+	// The canonical encoding is a valid encoding of the message, which
+	// decodes to a message equal to it. It may differ in size from the
+	// non-canonical encoding. Message sets are serialized deterministically,
+	// but are not canonical. Setting Canonical implies Deterministic.
+	//
+	// Use [IsCanonical] to check that an encoding is canonical.
+	Canonical bool
+
+	// Mask, if not nil, restricts the encoding to the fields it selects.
+	// Sub-messages named by a path prefix hold only the selected fields,
+	// and unknown fields are omitted from them.
+	//
+	// The encoding is that of the message projected onto the mask,
+	// but the message is not copied. Required fields are checked
+	// in the whole message.
+	Mask *Mask
+
 	// UseCachedSize indicates that the result of a previous Size call
 	// may be reused.
 	//
@@ -140,7 +181,14 @@ func (o MarshalOptions) MarshalState(in protoiface.MarshalInput) (protoiface.Mar
 func (o MarshalOptions) marshal(b []byte, m protoreflect.Message) (out protoiface.MarshalOutput, err error) {
 	allowPartial := o.AllowPartial
 	o.AllowPartial = true
-	if methods := protoMethods(m); methods != nil && methods.Marshal != nil &&
+	if o.Mask != nil {
+		t := o.Mask.tree
+		o.Mask = nil
+		out.Buf, err = o.marshalMessageMasked(b, m, t)
+	} else if o.Canonical {
+		o.Deterministic = true
+		out.Buf, err = o.marshalMessageCanonical(b, m)
+	} else if methods := protoMethods(m); methods != nil && methods.Marshal != nil &&
 		!(o.Deterministic && methods.Flags&protoiface.SupportMarshalDeterministic == 0) {
 		in := protoiface.MarshalInput{
 			Message: m,
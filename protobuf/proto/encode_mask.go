@@ -0,0 +1,74 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"github.com/golang/protobuf/protobuf/encoding/protowire"
+	"github.com/golang/protobuf/protobuf/internal/fieldmask"
+	"github.com/golang/protobuf/protobuf/internal/order"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+)
+
+// Mask selects the fields of a message for the Mask option of MarshalOptions.
+// It is safe for concurrent use.
+type Mask struct {
+	tree fieldmask.Tree
+}
+
+// NewMask returns a mask selecting the fields named by paths, in the syntax
+// of google.protobuf.FieldMask. A path which is a prefix of another selects
+// the field in its entirety. Paths which do not name a field are ignored,
+// and an empty mask selects no fields.
+func NewMask(paths []string) *Mask {
+	return &Mask{tree: fieldmask.New(paths)}
+}
+
+// marshalMessageMasked encodes the fields of m selected by t.
+func (o MarshalOptions) marshalMessageMasked(b []byte, m protoreflect.Message, t fieldmask.Tree) ([]byte, error) {
+	fieldOrder := order.AnyFieldOrder
+	switch {
+	case o.Canonical:
+		fieldOrder = order.NumberFieldOrder
+	case o.Deterministic:
+		fieldOrder = order.LegacyFieldOrder
+	}
+	var err error
+	order.RangeFields(m, fieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := t.Field(fd)
+		switch {
+		case !ok:
+		case sub != nil && fd.Message() != nil && !fd.IsList() && !fd.IsMap():
+			b, err = o.marshalSingularMasked(b, fd, v.Message(), sub)
+		case o.Canonical:
+			b, err = o.marshalFieldCanonical(b, fd, v)
+		default:
+			b, err = o.marshalField(b, fd, v)
+		}
+		return err == nil
+	})
+	return b, err
+}
+
+// marshalSingularMasked encodes the singular message field fd, holding m,
+// with the fields of m selected by t.
+func (o MarshalOptions) marshalSingularMasked(b []byte, fd protoreflect.FieldDescriptor, m protoreflect.Message, t fieldmask.Tree) ([]byte, error) {
+	var err error
+	if fd.Kind() == protoreflect.GroupKind {
+		b = protowire.AppendTag(b, fd.Number(), protowire.StartGroupType)
+		b, err = o.marshalMessageMasked(b, m, t)
+		if err != nil {
+			return b, err
+		}
+		return protowire.AppendTag(b, fd.Number(), protowire.EndGroupType), nil
+	}
+	b = protowire.AppendTag(b, fd.Number(), protowire.BytesType)
+	var pos int
+	b, pos = appendSpeculativeLength(b)
+	b, err = o.marshalMessageMasked(b, m, t)
+	if err != nil {
+		return b, err
+	}
+	return finishSpeculativeLength(b, pos), nil
+}
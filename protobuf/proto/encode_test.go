@@ -22,6 +22,7 @@ import (
 	orderpb "github.com/golang/protobuf/protobuf/internal/testprotos/order"
 	testpb "github.com/golang/protobuf/protobuf/internal/testprotos/test"
 	test3pb "github.com/golang/protobuf/protobuf/internal/testprotos/test3"
+	"github.com/golang/protobuf/protobuf/types/known/fieldmaskpb"
 )
 
 func TestEncode(t *testing.T) {
@@ -276,3 +277,179 @@ func TestEncodeEmpty(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeTo(t *testing.T) {
+	for _, test := range testValidMessages {
+		for _, want := range test.decodeTo {
+			opts := proto.MarshalOptions{
+				Deterministic: true,
+				AllowPartial:  test.partial,
+			}
+			wire, err := opts.Marshal(want)
+			if err != nil {
+				t.Fatalf("%s (%T): Marshal error: %v", test.desc, want, err)
+			}
+			var buf bytes.Buffer
+			if err := opts.MarshalTo(&buf, want); err != nil {
+				t.Errorf("%s (%T): MarshalTo error: %v", test.desc, want, err)
+				continue
+			}
+			if !bytes.Equal(buf.Bytes(), wire) {
+				t.Errorf("%s (%T): MarshalTo and Marshal disagree:\n%v", test.desc, want, cmp.Diff(wire, buf.Bytes()))
+			}
+		}
+	}
+}
+
+func TestEncodeToLarge(t *testing.T) {
+	// The message and some of its sub-messages are larger than the buffer
+	// of MarshalTo, which streams their fields.
+	value := string(bytes.Repeat([]byte("x"), 1000))
+	m := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		RepeatedString: []string{value, value},
+		MapStringString: map[string]string{
+			"a": value,
+			"b": value,
+		},
+		MapStringNestedMessage: map[string]*testpb.TestAllTypes_NestedMessage{},
+		OptionalBytes:          bytes.Repeat([]byte("y"), 100000),
+	}
+	for i := 0; i < 1000; i++ {
+		m.RepeatedInt32 = append(m.RepeatedInt32, int32(i))
+		m.RepeatedNestedMessage = append(m.RepeatedNestedMessage, &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(int32(i)),
+			Corecursive: &testpb.TestAllTypes{
+				OptionalString: proto.String(value),
+			},
+		})
+	}
+	for i := 0; i < 100; i++ {
+		m.MapStringNestedMessage[fmt.Sprint(i)] = &testpb.TestAllTypes_NestedMessage{
+			Corecursive: &testpb.TestAllTypes{
+				RepeatedString: []string{value, value, value},
+			},
+		}
+	}
+	m.MapStringNestedMessage["large"] = &testpb.TestAllTypes_NestedMessage{
+		Corecursive: proto.Clone(m).(*testpb.TestAllTypes),
+	}
+	m.ProtoReflect().SetUnknown(protowire.AppendBytes(protowire.AppendTag(nil, 10000, protowire.BytesType), []byte(value)))
+
+	opts := proto.MarshalOptions{Deterministic: true}
+	want, err := opts.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &limitWriter{}
+	if err := opts.MarshalTo(w, m); err != nil {
+		t.Fatalf("MarshalTo error: %v", err)
+	}
+	if !bytes.Equal(w.buf.Bytes(), want) {
+		t.Fatalf("MarshalTo and Marshal disagree: got %v bytes, want %v", w.buf.Len(), len(want))
+	}
+	if w.max >= len(want)/2 {
+		t.Errorf("MarshalTo wrote %v bytes at once, want it to stream the %v bytes", w.max, len(want))
+	}
+
+	got := &testpb.TestAllTypes{}
+	if err := proto.Unmarshal(w.buf.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(got, m) {
+		t.Errorf("round-trip through MarshalTo changed the message")
+	}
+}
+
+func TestEncodeToErrors(t *testing.T) {
+	for _, test := range testValidMessages {
+		if !test.partial {
+			continue
+		}
+		for _, m := range test.decodeTo {
+			var buf bytes.Buffer
+			if err := (proto.MarshalOptions{}).MarshalTo(&buf, m); err == nil {
+				t.Errorf("%s (%T): MarshalTo succeeded (want error)", test.desc, m)
+			}
+			if buf.Len() > 0 {
+				t.Errorf("%s (%T): MarshalTo wrote %v bytes of a message missing required fields", test.desc, m, buf.Len())
+			}
+		}
+	}
+
+	wantErr := errors.New("write error")
+	m := &testpb.TestAllTypes{OptionalString: proto.String("value")}
+	if err := (proto.MarshalOptions{}).MarshalTo(errWriter{wantErr}, m); err != wantErr {
+		t.Errorf("MarshalTo to a failing writer = %v, want %v", err, wantErr)
+	}
+}
+
+// limitWriter records what is written to it, and the size of the largest write.
+type limitWriter struct {
+	buf bytes.Buffer
+	max int
+}
+
+func (w *limitWriter) Write(b []byte) (int, error) {
+	if len(b) > w.max {
+		w.max = len(b)
+	}
+	return w.buf.Write(b)
+}
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestEncodeMask(t *testing.T) {
+	// MarshalOptions must remain comparable.
+	_ = proto.MarshalOptions{Mask: proto.NewMask(nil)} == proto.MarshalOptions{}
+
+	m := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		OptionalString: proto.String("x"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(2),
+			Corecursive: &testpb.TestAllTypes{
+				OptionalInt64:  proto.Int64(3),
+				OptionalUint32: proto.Uint32(4),
+			},
+		},
+		Optionalgroup:   &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(5)},
+		RepeatedInt32:   []int32{6, 7},
+		MapStringString: map[string]string{"k": "v", "l": "w"},
+		OneofField:      &testpb.TestAllTypes_OneofUint32{OneofUint32: 8},
+	}
+	m.ProtoReflect().SetUnknown(protowire.AppendVarint(protowire.AppendTag(nil, 10000, protowire.VarintType), 1))
+	for _, paths := range [][]string{
+		{},
+		{"optional_int32", "repeated_int32", "map_string_string", "oneof_uint32"},
+		{"optional_nested_message.corecursive.optional_int64", "OptionalGroup.a", "optional_bool"},
+		{"optional_nested_message.a", "optional_nested_message"},
+	} {
+		for _, opts := range []proto.MarshalOptions{
+			{Deterministic: true},
+			{Canonical: true},
+		} {
+			projected, err := fieldmaskpb.Project(m, &fieldmaskpb.FieldMask{Paths: paths})
+			if err != nil {
+				t.Fatalf("Project(%v) error: %v", paths, err)
+			}
+			want, err := opts.Marshal(projected)
+			if err != nil {
+				t.Fatalf("Marshal error: %v", err)
+			}
+			opts.Mask = proto.NewMask(paths)
+			got, err := opts.Marshal(m)
+			if err != nil {
+				t.Fatalf("Marshal with mask %v error: %v", paths, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Marshal with mask %v differs from Marshal of projection:\n%v", paths, cmp.Diff(want, got))
+			}
+			if size := opts.Size(m); size != len(got) {
+				t.Errorf("Size with mask %v = %v, want %v", paths, size, len(got))
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proto
+
+import (
+	"io"
+
+	"github.com/golang/protobuf/protobuf/encoding/protowire"
+	"github.com/golang/protobuf/protobuf/internal/encoding/messageset"
+	"github.com/golang/protobuf/protobuf/internal/errors"
+	"github.com/golang/protobuf/protobuf/internal/order"
+	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	"github.com/golang/protobuf/protobuf/runtime/protoiface"
+)
+
+// writerBufferSize is the size of the buffer through which MarshalTo
+// writes, and the size up to which it marshals sub-messages in one piece.
+const writerBufferSize = 32 << 10
+
+// MarshalTo writes the wire-format encoding of m to w.
+//
+// Unlike Marshal, MarshalTo does not hold the whole encoding in memory.
+// The sizes of m and its sub-messages are computed first, so that the
+// length prefixes of sub-messages can be written ahead of their fields,
+// which are then encoded through a buffer of bounded size.
+//
+// The encoding has the same size as that returned by Marshal, and the
+// same bytes if Deterministic is set. The message must not be modified
+// while MarshalTo is running.
+//
+// With the Canonical or Mask option, the encoding is not streamed:
+// it is held in memory in its entirety, as by Marshal, before it is written.
+func (o MarshalOptions) MarshalTo(w io.Writer, m Message) error {
+	// Treat nil message interface as an empty message; nothing to output.
+	if m == nil {
+		return nil
+	}
+	if o.Canonical || o.Mask != nil {
+		// The canonical and masked encodings differ in size from the
+		// one whose cached sizes are used to stream the message.
+		b, err := o.Marshal(m)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+	mr := m.ProtoReflect()
+	if !o.AllowPartial {
+		// Nothing is written if a required field is missing.
+		if err := checkInitialized(mr); err != nil {
+			return err
+		}
+		o.AllowPartial = true
+	}
+	if !o.UseCachedSize {
+		o.size(mr)
+		o.UseCachedSize = true
+	}
+	e := &writerEncoder{
+		o:   o,
+		w:   w,
+		buf: make([]byte, 0, writerBufferSize),
+	}
+	if err := e.message(mr, o.cachedSize(mr)); err != nil {
+		return err
+	}
+	return e.flush()
+}
+
+// cachedSize returns the size of m computed by a previous call to size.
+func (o MarshalOptions) cachedSize(m protoreflect.Message) int {
+	if methods := protoMethods(m); methods != nil && methods.Size != nil {
+		return methods.Size(protoiface.SizeInput{
+			Message: m,
+			Flags:   protoiface.MarshalUseCachedSize,
+		}).Size
+	}
+	return o.size(m)
+}
+
+// writerFieldOrder is the order in which the fast-path marshaler of
+// generated messages encodes fields: extensions sort first, by number,
+// followed by the other fields, by number.
+var writerFieldOrder order.FieldOrder = func(x, y protoreflect.FieldDescriptor) bool {
+	if x.IsExtension() != y.IsExtension() {
+		return x.IsExtension()
+	}
+	return x.Number() < y.Number()
+}
+
+type writerEncoder struct {
+	o   MarshalOptions
+	w   io.Writer
+	buf []byte
+	n   int // number of bytes encoded, including those in buf
+}
+
+func (e *writerEncoder) flush() error {
+	if len(e.buf) == 0 {
+		return nil
+	}
+	_, err := e.w.Write(e.buf)
+	e.buf = e.buf[:0]
+	return err
+}
+
+// append appends b to the buffer, which is flushed if it is full.
+func (e *writerEncoder) append(b []byte) error {
+	if len(e.buf)+len(b) <= writerBufferSize {
+		e.buf = append(e.buf, b...)
+		e.n += len(b)
+		return nil
+	}
+	if err := e.flush(); err != nil {
+		return err
+	}
+	if len(b) >= writerBufferSize {
+		e.n += len(b)
+		_, err := e.w.Write(b)
+		return err
+	}
+	e.buf = append(e.buf, b...)
+	e.n += len(b)
+	return nil
+}
+
+// encode appends the bytes appended to the buffer by f.
+func (e *writerEncoder) encode(f func(b []byte) ([]byte, error)) error {
+	if len(e.buf) >= writerBufferSize {
+		if err := e.flush(); err != nil {
+			return err
+		}
+	}
+	n := len(e.buf)
+	b, err := f(e.buf)
+	e.buf = b
+	e.n += len(b) - n
+	return err
+}
+
+func (e *writerEncoder) tag(num protowire.Number, typ protowire.Type) error {
+	return e.encode(func(b []byte) ([]byte, error) {
+		return protowire.AppendTag(b, num, typ), nil
+	})
+}
+
+func (e *writerEncoder) length(n int) error {
+	return e.encode(func(b []byte) ([]byte, error) {
+		return protowire.AppendVarint(b, uint64(n)), nil
+	})
+}
+
+// message encodes the fields of m, whose size is size.
+func (e *writerEncoder) message(m protoreflect.Message, size int) error {
+	if size <= writerBufferSize || messageset.IsMessageSet(m.Descriptor()) {
+		return e.encode(func(b []byte) ([]byte, error) {
+			return e.o.marshalMessage(b, m)
+		})
+	}
+	start := e.n
+	var err error
+	order.RangeFields(m, writerFieldOrder, func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		err = e.field(fd, v)
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+	if err := e.append(m.GetUnknown()); err != nil {
+		return err
+	}
+	if e.n-start != size {
+		return errors.New("message %v changed size while being marshaled", m.Descriptor().FullName())
+	}
+	return nil
+}
+
+func (e *writerEncoder) field(fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch {
+	case fd.IsList():
+		return e.list(fd, v.List())
+	case fd.IsMap():
+		return e.mapEntries(fd, v.Map())
+	default:
+		return e.singular(fd, v)
+	}
+}
+
+func (e *writerEncoder) singular(fd protoreflect.FieldDescriptor, v protoreflect.Value) error {
+	switch fd.Kind() {
+	case protoreflect.MessageKind:
+		m := v.Message()
+		size := e.o.cachedSize(m)
+		if err := e.tag(fd.Number(), protowire.BytesType); err != nil {
+			return err
+		}
+		if err := e.length(size); err != nil {
+			return err
+		}
+		return e.message(m, size)
+	case protoreflect.GroupKind:
+		m := v.Message()
+		if err := e.tag(fd.Number(), protowire.StartGroupType); err != nil {
+			return err
+		}
+		if err := e.message(m, e.o.cachedSize(m)); err != nil {
+			return err
+		}
+		return e.tag(fd.Number(), protowire.EndGroupType)
+	case protoreflect.BytesKind:
+		if b := v.Bytes(); len(b) > writerBufferSize {
+			// Large values are written directly, without being copied
+			// to the buffer.
+			if err := e.tag(fd.Number(), protowire.BytesType); err != nil {
+				return err
+			}
+			if err := e.length(len(b)); err != nil {
+				return err
+			}
+			return e.append(b)
+		}
+	}
+	return e.encode(func(b []byte) ([]byte, error) {
+		b = protowire.AppendTag(b, fd.Number(), wireTypes[fd.Kind()])
+		return e.o.marshalSingular(b, fd, v)
+	})
+}
+
+func (e *writerEncoder) list(fd protoreflect.FieldDescriptor, list protoreflect.List) error {
+	if fd.IsPacked() && list.Len() > 0 {
+		size := 0
+		for i, llen := 0, list.Len(); i < llen; i++ {
+			size += e.o.sizeSingular(fd.Number(), fd.Kind(), list.Get(i))
+		}
+		if err := e.tag(fd.Number(), protowire.BytesType); err != nil {
+			return err
+		}
+		if err := e.length(size); err != nil {
+			return err
+		}
+		for i, llen := 0, list.Len(); i < llen; i++ {
+			v := list.Get(i)
+			if err := e.encode(func(b []byte) ([]byte, error) {
+				return e.o.marshalSingular(b, fd, v)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i, llen := 0, list.Len(); i < llen; i++ {
+		if err := e.singular(fd, list.Get(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *writerEncoder) mapEntries(fd protoreflect.FieldDescriptor, mapv protoreflect.Map) error {
+	keyf := fd.MapKey()
+	valf := fd.MapValue()
+	keyOrder := order.AnyKeyOrder
+	if e.o.Deterministic {
+		keyOrder = order.GenericKeyOrder
+	}
+	var err error
+	order.RangeEntries(mapv, keyOrder, func(key protoreflect.MapKey, value protoreflect.Value) bool {
+		size := e.o.sizeField(keyf, key.Value())
+		if valf.Kind() == protoreflect.MessageKind {
+			size += protowire.SizeTag(valf.Number()) + protowire.SizeBytes(e.o.cachedSize(value.Message()))
+		} else {
+			size += e.o.sizeField(valf, value)
+		}
+		if err = e.tag(fd.Number(), protowire.BytesType); err != nil {
+			return false
+		}
+		if err = e.length(size); err != nil {
+			return false
+		}
+		if err = e.singular(keyf, key.Value()); err != nil {
+			return false
+		}
+		err = e.singular(valf, value)
+		return err == nil
+	})
+	return err
+}
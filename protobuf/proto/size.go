@@ -30,6 +30,13 @@ func (o MarshalOptions) Size(m Message) int {
 // For profiling purposes, avoid changing the name of this function or
 // introducing other code paths for size that do not go through this.
 func (o MarshalOptions) size(m protoreflect.Message) (size int) {
+	if o.Canonical || o.Mask != nil {
+		// The canonical and masked encodings are not produced by the
+		// fast-path marshalers, which cannot compute their size either.
+		o.AllowPartial = true
+		out, _ := o.marshal(nil, m)
+		return len(out.Buf)
+	}
 	methods := protoMethods(m)
 	if methods != nil && methods.Size != nil {
 		out := methods.Size(protoiface.SizeInput{
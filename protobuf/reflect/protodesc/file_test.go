@@ -1255,3 +1255,37 @@ func TestToFileDescriptorProtoPlaceHolder(t *testing.T) {
 		t.Errorf("placeholder file descriptor proto is not valid: %s", err)
 	}
 }
+
+func TestToFileDescriptorProtoEditions(t *testing.T) {
+	want := mustParseFile(`
+		syntax:    "editions"
+		edition:   EDITION_2023
+		name:      "editions.proto"
+		package:   "test.editions"
+		options: {
+			features: {
+				field_presence: IMPLICIT
+			}
+		}
+		message_type: [{
+			name:  "Message"
+			field: [
+				{name:"foo" number:1 label:LABEL_OPTIONAL type:TYPE_STRING json_name:"foo"},
+				{name:"bar" number:2 label:LABEL_OPTIONAL type:TYPE_INT32 json_name:"bar" options:{features:{field_presence:LEGACY_REQUIRED}}},
+				{name:"baz" number:3 label:LABEL_OPTIONAL type:TYPE_MESSAGE type_name:".test.editions.Message.Baz" json_name:"baz" options:{features:{message_encoding:DELIMITED}}}
+			]
+			nested_type: [{name:"Baz"}]
+		}]
+	`)
+	fd, err := NewFile(want, &protoregistry.Files{})
+	if err != nil {
+		t.Fatalf("NewFile() error: %v", err)
+	}
+	got := ToFileDescriptorProto(fd)
+	if !proto.Equal(got, want) {
+		t.Errorf("ToFileDescriptorProto() mismatch:\ngot:  %v\nwant: %v", got, want)
+	}
+	if _, err := NewFile(got, &protoregistry.Files{}); err != nil {
+		t.Errorf("NewFile(ToFileDescriptorProto()) error: %v", err)
+	}
+}
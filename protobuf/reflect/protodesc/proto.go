@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/golang/protobuf/protobuf/internal/encoding/defval"
+	"github.com/golang/protobuf/protobuf/internal/filedesc"
 	"github.com/golang/protobuf/protobuf/internal/strs"
 	"github.com/golang/protobuf/protobuf/proto"
 	"github.com/golang/protobuf/protobuf/reflect/protoreflect"
@@ -73,6 +74,9 @@ func ToFileDescriptorProto(file protoreflect.FileDescriptor) *descriptorpb.FileD
 	if syntax := file.Syntax(); syntax != protoreflect.Proto2 && syntax.IsValid() {
 		p.Syntax = proto.String(file.Syntax().String())
 	}
+	if fd, ok := file.(interface{ Edition() filedesc.Edition }); ok && file.Syntax() == protoreflect.Editions {
+		p.Edition = toEditionProto(fd.Edition()).Enum()
+	}
 	return p
 }
 
@@ -150,6 +154,18 @@ func ToFieldDescriptorProto(field protoreflect.FieldDescriptor) *descriptorpb.Fi
 			p.JsonName = proto.String(field.JSONName())
 		}
 	}
+	if field.Syntax() == protoreflect.Editions {
+		// Editions have no group keyword. Delimited encoding is carried
+		// by the message_encoding feature in the field options instead.
+		if p.GetType() == descriptorpb.FieldDescriptorProto_TYPE_GROUP {
+			p.Type = descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum()
+		}
+		// Editions have no required keyword. Required fields are marked
+		// by the field_presence feature in the field options instead.
+		if p.GetLabel() == descriptorpb.FieldDescriptorProto_LABEL_REQUIRED {
+			p.Label = descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+		}
+	}
 	if field.Syntax() == protoreflect.Proto3 && field.HasOptionalKeyword() {
 		p.Proto3Optional = proto.Bool(true)
 	}
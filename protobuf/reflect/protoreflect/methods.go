@@ -54,6 +54,9 @@ type (
 			FindExtensionByNumber(message FullName, field FieldNumber) (ExtensionType, error)
 		}
 		Depth int
+		Arena interface {
+			ProtoInternal(pragma.DoNotImplement)
+		}
 	}
 	unmarshalOutput = struct {
 		pragma.NoUnkeyedLiterals
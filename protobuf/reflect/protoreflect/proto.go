@@ -161,7 +161,7 @@ const (
 // IsValid reports whether the syntax is valid.
 func (s Syntax) IsValid() bool {
 	switch s {
-	case Proto2, Proto3:
+	case Proto2, Proto3, Editions:
 		return true
 	default:
 		return false
@@ -189,6 +189,8 @@ func (s Syntax) GoString() string {
 		return "Proto2"
 	case Proto3:
 		return "Proto3"
+	case Editions:
+		return "Editions"
 	default:
 		return fmt.Sprintf("Syntax(%d)", s)
 	}
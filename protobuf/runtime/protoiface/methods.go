@@ -104,6 +104,14 @@ type UnmarshalInput = struct {
 		FindExtensionByNumber(message protoreflect.FullName, field protoreflect.FieldNumber) (protoreflect.ExtensionType, error)
 	}
 	Depth int
+	Arena Arena // allocates the values of the message, if not nil
+}
+
+// Arena is an opaque allocator for the values of unmarshaled messages,
+// as set by proto.UnmarshalOptions.Arena. It is only implemented by
+// the protobuf runtime, and may be passed on to other Unmarshal methods.
+type Arena = interface {
+	ProtoInternal(pragma.DoNotImplement)
 }
 
 // UnmarshalOutput is output from the Unmarshal method.
@@ -119,6 +127,12 @@ type UnmarshalInputFlags = uint8
 
 const (
 	UnmarshalDiscardUnknown UnmarshalInputFlags = 1 << iota
+
+	// UnmarshalAliasBytes permits bytes fields to alias the input buffer.
+	UnmarshalAliasBytes
+
+	// UnmarshalAliasStrings permits string fields to alias the input buffer.
+	UnmarshalAliasStrings
 )
 
 // UnmarshalOutputFlags are output from the Unmarshal method.
@@ -34,6 +34,7 @@ type (
 	MessageState     = impl.MessageState
 	SizeCache        = impl.SizeCache
 	WeakFields       = impl.WeakFields
+	LazyFields       = impl.LazyFields
 	UnknownFields    = impl.UnknownFields
 	ExtensionFields  = impl.ExtensionFields
 	ExtensionFieldV1 = impl.ExtensionField
@@ -73,6 +73,20 @@
 // IsValid needs to be passed the target message type as an input since the
 // FieldMask message itself does not store the message type that the set of paths
 // are for.
+//
+// # Applying a FieldMask
+//
+// The Project function returns a copy of a message holding only the fields
+// selected by a FieldMask, and the MergeMasked function applies the selected
+// fields of one message to another, as in an update operation:
+//
+//	var resource, update *descriptorpb.DescriptorProto
+//	if err := fieldmaskpb.MergeMasked(resource, update, fm); err != nil {
+//		... // handle error
+//	}
+//
+// To encode only the selected fields of a message, without copying it,
+// set the Mask option of proto.MarshalOptions to proto.NewMask(fm.GetPaths()).
 package fieldmaskpb
 
 import (
@@ -465,6 +479,194 @@ func rangeFields(path string, f func(field string) bool) bool {
 	}
 }
 
+// Project returns a copy of m which holds only the fields selected by
+// the paths of the mask, which must be valid for the message type of m.
+// A nil or empty mask selects no fields.
+func Project(m proto.Message, mask *FieldMask) (proto.Message, error) {
+	if err := checkPaths(m, mask.GetPaths()); err != nil {
+		return nil, err
+	}
+	src := m.ProtoReflect()
+	dst := src.New()
+	projectMessage(dst, src, newPathTree(mask.GetPaths()))
+	return dst.Interface(), nil
+}
+
+func projectMessage(dst, src protoreflect.Message, t pathTree) {
+	src.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		sub, ok := t.field(fd)
+		switch {
+		case !ok:
+		case sub == nil:
+			mergeField(dst, fd, v)
+		default:
+			projectMessage(dst.Mutable(fd).Message(), v.Message(), sub)
+		}
+		return true
+	})
+}
+
+// MergeOptions configures MergeMasked.
+type MergeOptions struct {
+	// ReplaceRepeated specifies that repeated and map fields selected by
+	// the mask are replaced by those of the source message.
+	// By default, elements of the source are appended to repeated fields,
+	// and entries of the source are added to maps, replacing those with
+	// the same key.
+	ReplaceRepeated bool
+
+	// ReplaceMessages specifies that message fields named by the last
+	// element of a path are replaced by those of the source message.
+	// By default, they are merged into the destination message.
+	ReplaceMessages bool
+}
+
+// MergeMasked updates dst with the fields of src selected by the paths of
+// the mask, as described for update operations in the documentation of
+// FieldMask. It is equivalent to MergeOptions{}.MergeMasked(dst, src, mask).
+func MergeMasked(dst, src proto.Message, mask *FieldMask) error {
+	return MergeOptions{}.MergeMasked(dst, src, mask)
+}
+
+// MergeMasked updates dst with the fields of src selected by the paths of
+// the mask, which must be valid for the message type of dst and src.
+// A selected field is reset in dst if it is not populated in src.
+// Fields which are not selected are left untouched.
+// A nil or empty mask selects no fields.
+func (o MergeOptions) MergeMasked(dst, src proto.Message, mask *FieldMask) error {
+	dstMsg, srcMsg := dst.ProtoReflect(), src.ProtoReflect()
+	if dstMsg.Descriptor().FullName() != srcMsg.Descriptor().FullName() {
+		return protoimpl.X.NewError("mismatching message types %q and %q", dstMsg.Descriptor().FullName(), srcMsg.Descriptor().FullName())
+	}
+	if err := checkPaths(dst, mask.GetPaths()); err != nil {
+		return err
+	}
+	o.mergeMessage(dstMsg, srcMsg, newPathTree(mask.GetPaths()))
+	return nil
+}
+
+func (o MergeOptions) mergeMessage(dst, src protoreflect.Message, t pathTree) {
+	fields := dst.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		sub, ok := t.field(fd)
+		switch {
+		case !ok:
+		case sub != nil:
+			if dst.Has(fd) || src.Has(fd) {
+				o.mergeMessage(dst.Mutable(fd).Message(), src.Get(fd).Message(), sub)
+			}
+		case fd.IsList() || fd.IsMap():
+			if o.ReplaceRepeated {
+				dst.Clear(fd)
+			}
+			if src.Has(fd) {
+				mergeField(dst, fd, src.Get(fd))
+			}
+		case fd.Message() != nil:
+			if o.ReplaceMessages || !src.Has(fd) {
+				dst.Clear(fd)
+			}
+			if src.Has(fd) {
+				mergeField(dst, fd, src.Get(fd))
+			}
+		default:
+			if src.Has(fd) {
+				mergeField(dst, fd, src.Get(fd))
+			} else {
+				dst.Clear(fd)
+			}
+		}
+	}
+}
+
+// mergeField merges a copy of the value v of the field fd into dst.
+func mergeField(dst protoreflect.Message, fd protoreflect.FieldDescriptor, v protoreflect.Value) {
+	switch {
+	case fd.IsList():
+		dstList, srcList := dst.Mutable(fd).List(), v.List()
+		for i := 0; i < srcList.Len(); i++ {
+			dstList.Append(copyValue(fd, srcList.Get(i)))
+		}
+	case fd.IsMap():
+		dstMap := dst.Mutable(fd).Map()
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			dstMap.Set(k, copyValue(fd.MapValue(), v))
+			return true
+		})
+	case fd.Message() != nil:
+		proto.Merge(dst.Mutable(fd).Message().Interface(), v.Message().Interface())
+	default:
+		dst.Set(fd, copyValue(fd, v))
+	}
+}
+
+// copyValue returns a copy of the singular value v of the field fd.
+func copyValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) protoreflect.Value {
+	switch {
+	case fd.Message() != nil:
+		return protoreflect.ValueOfMessage(proto.Clone(v.Message().Interface()).ProtoReflect())
+	case fd.Kind() == protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes(append([]byte(nil), v.Bytes()...))
+	}
+	return v
+}
+
+// checkPaths reports an error for the first path which is not valid
+// for the message type of m.
+func checkPaths(m proto.Message, paths []string) error {
+	if n := numValidPaths(m, paths); n < len(paths) {
+		name := m.ProtoReflect().Descriptor().FullName()
+		return protoimpl.X.NewError("invalid path %q for message %q", paths[n], name)
+	}
+	return nil
+}
+
+// pathTree is a set of paths. Each key is the name of a field of a message,
+// and its value holds the paths within that field. A nil value selects the
+// field in its entirety.
+type pathTree map[string]pathTree
+
+func newPathTree(paths []string) pathTree {
+	t := pathTree{}
+	for _, path := range paths {
+		node, name := t, ""
+		if !rangeFields(path, func(field string) bool {
+			if name != "" {
+				sub, ok := node[name]
+				if ok && sub == nil {
+					return false // the field is already selected in its entirety
+				}
+				if !ok {
+					sub = pathTree{}
+					node[name] = sub
+				}
+				node = sub
+			}
+			name = field
+			return true
+		}) {
+			continue
+		}
+		node[name] = nil
+	}
+	return t
+}
+
+// field returns the paths within the field fd, and reports whether any
+// part of the field is selected. The name of a group is its message name.
+func (t pathTree) field(fd protoreflect.FieldDescriptor) (pathTree, bool) {
+	if fd.IsExtension() {
+		return nil, false
+	}
+	name := string(fd.Name())
+	if fd.Kind() == protoreflect.GroupKind {
+		name = string(fd.Message().Name())
+	}
+	sub, ok := t[name]
+	return sub, ok
+}
+
 func (x *FieldMask) Reset() {
 	*x = FieldMask{}
 	if protoimpl.UnsafeEnabled {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/golang/protobuf/protobuf/proto"
+	"github.com/golang/protobuf/protobuf/testing/protocmp"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 
@@ -336,3 +337,182 @@ func TestIsValid(t *testing.T) {
 		})
 	}
 }
+
+func TestProject(t *testing.T) {
+	m := &testpb.TestAllTypes{
+		OptionalInt32:  proto.Int32(1),
+		OptionalString: proto.String("x"),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			A: proto.Int32(2),
+			Corecursive: &testpb.TestAllTypes{
+				OptionalInt64:  proto.Int64(3),
+				OptionalUint32: proto.Uint32(4),
+			},
+		},
+		Optionalgroup:   &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(5)},
+		RepeatedInt32:   []int32{6, 7},
+		MapStringString: map[string]string{"k": "v"},
+		OneofField:      &testpb.TestAllTypes_OneofUint32{OneofUint32: 8},
+	}
+	tests := []struct {
+		paths []string
+		want  proto.Message
+	}{{
+		paths: nil,
+		want:  &testpb.TestAllTypes{},
+	}, {
+		paths: []string{"optional_int32", "repeated_int32", "map_string_string", "oneof_uint32", "oneof_string"},
+		want: &testpb.TestAllTypes{
+			OptionalInt32:   proto.Int32(1),
+			RepeatedInt32:   []int32{6, 7},
+			MapStringString: map[string]string{"k": "v"},
+			OneofField:      &testpb.TestAllTypes_OneofUint32{OneofUint32: 8},
+		},
+	}, {
+		paths: []string{"optional_nested_message.corecursive.optional_int64", "OptionalGroup", "optional_bool"},
+		want: &testpb.TestAllTypes{
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				Corecursive: &testpb.TestAllTypes{
+					OptionalInt64: proto.Int64(3),
+				},
+			},
+			Optionalgroup: &testpb.TestAllTypes_OptionalGroup{A: proto.Int32(5)},
+		},
+	}, {
+		paths: []string{"optional_nested_message.a", "optional_nested_message"},
+		want: &testpb.TestAllTypes{
+			OptionalNestedMessage: m.OptionalNestedMessage,
+		},
+	}}
+	for _, tt := range tests {
+		got, err := fmpb.Project(m, &fmpb.FieldMask{Paths: tt.paths})
+		if err != nil {
+			t.Errorf("Project(%v) error: %v", tt.paths, err)
+			continue
+		}
+		if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("Project(%v) mismatch (-want +got):\n%s", tt.paths, diff)
+		}
+	}
+
+	// The projection does not share memory with the message.
+	got, _ := fmpb.Project(m, &fmpb.FieldMask{Paths: []string{"optional_nested_message"}})
+	got.(*testpb.TestAllTypes).OptionalNestedMessage.A = proto.Int32(0)
+	if m.OptionalNestedMessage.GetA() != 2 {
+		t.Errorf("modifying the projection modified the message")
+	}
+
+	if _, err := fmpb.Project(m, &fmpb.FieldMask{Paths: []string{"repeated_int32.foo"}}); err == nil {
+		t.Errorf("Project with invalid path succeeded, want error")
+	}
+}
+
+func TestMergeMasked(t *testing.T) {
+	dst := func() *testpb.TestAllTypes {
+		return &testpb.TestAllTypes{
+			OptionalInt32: proto.Int32(1),
+			OptionalInt64: proto.Int64(2),
+			OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+				A: proto.Int32(3),
+				Corecursive: &testpb.TestAllTypes{
+					OptionalInt32: proto.Int32(4),
+					OptionalInt64: proto.Int64(5),
+				},
+			},
+			RepeatedInt32:   []int32{1},
+			MapStringString: map[string]string{"a": "1", "b": "2"},
+			OneofField:      &testpb.TestAllTypes_OneofUint32{OneofUint32: 6},
+		}
+	}
+	src := &testpb.TestAllTypes{
+		OptionalInt64: proto.Int64(20),
+		OptionalNestedMessage: &testpb.TestAllTypes_NestedMessage{
+			Corecursive: &testpb.TestAllTypes{
+				OptionalInt64: proto.Int64(50),
+			},
+		},
+		RepeatedInt32:   []int32{2},
+		MapStringString: map[string]string{"b": "20", "c": "30"},
+		OneofField:      &testpb.TestAllTypes_OneofString{OneofString: "x"},
+	}
+	tests := []struct {
+		desc  string
+		opts  fmpb.MergeOptions
+		paths []string
+		want  func(m *testpb.TestAllTypes)
+	}{{
+		desc:  "empty mask",
+		paths: nil,
+		want:  func(m *testpb.TestAllTypes) {},
+	}, {
+		desc:  "scalars",
+		paths: []string{"optional_int32", "optional_int64"},
+		want: func(m *testpb.TestAllTypes) {
+			m.OptionalInt32 = nil
+			m.OptionalInt64 = proto.Int64(20)
+		},
+	}, {
+		desc:  "nested path",
+		paths: []string{"optional_nested_message.corecursive.optional_int64"},
+		want: func(m *testpb.TestAllTypes) {
+			m.OptionalNestedMessage.Corecursive.OptionalInt64 = proto.Int64(50)
+		},
+	}, {
+		desc:  "merge message",
+		paths: []string{"optional_nested_message"},
+		want: func(m *testpb.TestAllTypes) {
+			m.OptionalNestedMessage.Corecursive.OptionalInt64 = proto.Int64(50)
+		},
+	}, {
+		desc:  "replace message",
+		opts:  fmpb.MergeOptions{ReplaceMessages: true},
+		paths: []string{"optional_nested_message"},
+		want: func(m *testpb.TestAllTypes) {
+			m.OptionalNestedMessage = src.OptionalNestedMessage
+		},
+	}, {
+		desc:  "clear message",
+		paths: []string{"optional_foreign_message", "optional_nested_message.corecursive.optional_nested_message"},
+		want:  func(m *testpb.TestAllTypes) {},
+	}, {
+		desc:  "append repeated",
+		paths: []string{"repeated_int32", "map_string_string"},
+		want: func(m *testpb.TestAllTypes) {
+			m.RepeatedInt32 = []int32{1, 2}
+			m.MapStringString = map[string]string{"a": "1", "b": "20", "c": "30"}
+		},
+	}, {
+		desc:  "replace repeated",
+		opts:  fmpb.MergeOptions{ReplaceRepeated: true},
+		paths: []string{"repeated_int32", "map_string_string", "repeated_int64"},
+		want: func(m *testpb.TestAllTypes) {
+			m.RepeatedInt32 = []int32{2}
+			m.MapStringString = map[string]string{"b": "20", "c": "30"}
+		},
+	}, {
+		desc:  "oneof",
+		paths: []string{"oneof_uint32", "oneof_string"},
+		want: func(m *testpb.TestAllTypes) {
+			m.OneofField = &testpb.TestAllTypes_OneofString{OneofString: "x"}
+		},
+	}}
+	for _, tt := range tests {
+		got := dst()
+		if err := tt.opts.MergeMasked(got, src, &fmpb.FieldMask{Paths: tt.paths}); err != nil {
+			t.Errorf("%s: MergeMasked error: %v", tt.desc, err)
+			continue
+		}
+		want := dst()
+		tt.want(want)
+		if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("%s: MergeMasked mismatch (-want +got):\n%s", tt.desc, diff)
+		}
+	}
+
+	if err := fmpb.MergeMasked(dst(), src, &fmpb.FieldMask{Paths: []string{"<INVALID>"}}); err == nil {
+		t.Errorf("MergeMasked with invalid path succeeded, want error")
+	}
+	if err := fmpb.MergeMasked(dst(), &fmpb.FieldMask{}, &fmpb.FieldMask{}); err == nil {
+		t.Errorf("MergeMasked of mismatching types succeeded, want error")
+	}
+}
@@ -0,0 +1,96 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/golang/protobuf/protoc-gen-go/gohttp/gohttp.proto
+
+package gohttp
+
+import (
+	protoreflect "github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	protoimpl "github.com/golang/protobuf/protobuf/runtime/protoimpl"
+	descriptorpb "github.com/golang/protobuf/protobuf/types/descriptorpb"
+	reflect "reflect"
+)
+
+var file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.MethodOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50602,
+		Name:          "gohttp.route",
+		Tag:           "bytes,50602,opt,name=route",
+		Filename:      "github.com/golang/protobuf/protoc-gen-go/gohttp/gohttp.proto",
+	},
+}
+
+// Extension fields to descriptorpb.MethodOptions.
+var (
+	// The route that the http plugin of protoc-gen-go serves the method at,
+	// written as an optional HTTP method followed by a path. For example:
+	//
+	//   rpc GetUser(GetUserRequest) returns (GetUserResponse) {
+	//     option (gohttp.route) = "GET /v1/user";
+	//   }
+	//
+	// The HTTP method defaults to POST, and the route defaults to
+	// "POST /package.Service/Method". The path is matched exactly, so it may
+	// not contain the wildcards of net/http patterns, written in braces, or end
+	// in a slash.
+	//
+	// optional string route = 50602;
+	E_Route = &file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_extTypes[0]
+)
+
+var File_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto protoreflect.FileDescriptor
+
+var file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_rawDesc = []byte{
+	0x0a, 0x3c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c,
+	0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x68, 0x74, 0x74,
+	0x70, 0x2f, 0x67, 0x6f, 0x68, 0x74, 0x74, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x67, 0x6f, 0x68, 0x74, 0x74, 0x70, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
+	0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x36, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x18, 0xaa, 0x8b, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67,
+	0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x68,
+	0x74, 0x74, 0x70, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_goTypes = []interface{}{
+	(*descriptorpb.MethodOptions)(nil), // 0: google.protobuf.MethodOptions
+}
+var file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_depIdxs = []int32{
+	0, // 0: gohttp.route:extendee -> google.protobuf.MethodOptions
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_init() }
+func file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_init() {
+	if File_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_goTypes,
+		DependencyIndexes: file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_depIdxs,
+		ExtensionInfos:    file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_extTypes,
+	}.Build()
+	File_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto = out.File
+	file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_rawDesc = nil
+	file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_goTypes = nil
+	file_github_com_golang_protobuf_protoc_gen_go_gohttp_gohttp_proto_depIdxs = nil
+}
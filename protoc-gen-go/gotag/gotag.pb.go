@@ -0,0 +1,93 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/golang/protobuf/protoc-gen-go/gotag/gotag.proto
+
+package gotag
+
+import (
+	protoreflect "github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	protoimpl "github.com/golang/protobuf/protobuf/runtime/protoimpl"
+	descriptorpb "github.com/golang/protobuf/protobuf/types/descriptorpb"
+	reflect "reflect"
+)
+
+var file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*string)(nil),
+		Field:         50601,
+		Name:          "gotag.tags",
+		Tag:           "bytes,50601,opt,name=tags",
+		Filename:      "github.com/golang/protobuf/protoc-gen-go/gotag/gotag.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// Additional Go struct tags for the generated field, written in the same
+	// form as a Go struct tag. For example:
+	//
+	//   string user_id = 1 [(gotag.tags) = "db:\"uid\" bson:\"_id\""];
+	//
+	// A tag replaces the json, bson or extra tag that protoc-gen-go would
+	// otherwise generate under the same key, and a tag with an empty value
+	// removes it. The protobuf tags and the go tag cannot be overridden.
+	//
+	// optional string tags = 50601;
+	E_Tags = &file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_extTypes[0]
+)
+
+var File_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto protoreflect.FileDescriptor
+
+var file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_rawDesc = []byte{
+	0x0a, 0x3a, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c,
+	0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x74, 0x61, 0x67,
+	0x2f, 0x67, 0x6f, 0x74, 0x61, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x67, 0x6f,
+	0x74, 0x61, 0x67, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x3a, 0x33, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x12, 0x1d, 0x2e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e,
+	0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xa9, 0x8b, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x61, 0x67, 0x73, 0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x2d,
+	0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x74, 0x61, 0x67, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_goTypes = []interface{}{
+	(*descriptorpb.FieldOptions)(nil), // 0: google.protobuf.FieldOptions
+}
+var file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_depIdxs = []int32{
+	0, // 0: gotag.tags:extendee -> google.protobuf.FieldOptions
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_init() }
+func file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_init() {
+	if File_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   0,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_goTypes,
+		DependencyIndexes: file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_depIdxs,
+		ExtensionInfos:    file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_extTypes,
+	}.Build()
+	File_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto = out.File
+	file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_rawDesc = nil
+	file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_goTypes = nil
+	file_github_com_golang_protobuf_protoc_gen_go_gotag_gotag_proto_depIdxs = nil
+}
@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: github.com/golang/protobuf/protoc-gen-go/govalidate/govalidate.proto
+
+package govalidate
+
+import (
+	protoreflect "github.com/golang/protobuf/protobuf/reflect/protoreflect"
+	protoimpl "github.com/golang/protobuf/protobuf/runtime/protoimpl"
+	descriptorpb "github.com/golang/protobuf/protobuf/types/descriptorpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+// FieldRules are the constraints on a field that are checked by the Validate
+// method generated by the validate plugin of protoc-gen-go. For example:
+//
+//	string name = 1 [(govalidate.rules) = {min_len: 1, max_len: 64}];
+//
+// The rules on a repeated field other than min_items and max_items apply to
+// each of its elements. The rules on a field with presence other than
+// required only apply if the field is set.
+type FieldRules struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The field must be set. A field without presence must not have the
+	// zero value.
+	Required *bool `protobuf:"varint,1,opt,name=required" json:"required,omitempty" bson:"required"`
+	// The minimum and maximum length of a string in characters,
+	// or of bytes in bytes.
+	MinLen *uint64 `protobuf:"varint,2,opt,name=min_len,json=minLen" json:"min_len,omitempty" bson:"min_len"`
+	MaxLen *uint64 `protobuf:"varint,3,opt,name=max_len,json=maxLen" json:"max_len,omitempty" bson:"max_len"`
+	// The inclusive range of a numeric field.
+	Min *float64 `protobuf:"fixed64,4,opt,name=min" json:"min,omitempty" bson:"min"`
+	Max *float64 `protobuf:"fixed64,5,opt,name=max" json:"max,omitempty" bson:"max"`
+	// An RE2 regular expression that a string must match.
+	Pattern *string `protobuf:"bytes,6,opt,name=pattern" json:"pattern,omitempty" bson:"pattern"`
+	// An enum field must have one of the values declared by the enum.
+	DefinedOnly *bool `protobuf:"varint,7,opt,name=defined_only,json=definedOnly" json:"defined_only,omitempty" bson:"defined_only"`
+	// The minimum and maximum number of elements of a repeated or map field.
+	MinItems *uint64 `protobuf:"varint,8,opt,name=min_items,json=minItems" json:"min_items,omitempty" bson:"min_items"`
+	MaxItems *uint64 `protobuf:"varint,9,opt,name=max_items,json=maxItems" json:"max_items,omitempty" bson:"max_items"`
+}
+
+func (x *FieldRules) Reset() {
+	*x = FieldRules{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FieldRules) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FieldRules) ProtoMessage() {}
+
+func (x *FieldRules) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FieldRules.ProtoReflect.Descriptor instead.
+func (*FieldRules) Descriptor() ([]byte, []int) {
+	return file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *FieldRules) GetRequired() bool {
+	if x != nil && x.Required != nil {
+		return *x.Required
+	}
+	return false
+}
+
+func (x *FieldRules) GetMinLen() uint64 {
+	if x != nil && x.MinLen != nil {
+		return *x.MinLen
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMaxLen() uint64 {
+	if x != nil && x.MaxLen != nil {
+		return *x.MaxLen
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *FieldRules) GetPattern() string {
+	if x != nil && x.Pattern != nil {
+		return *x.Pattern
+	}
+	return ""
+}
+
+func (x *FieldRules) GetDefinedOnly() bool {
+	if x != nil && x.DefinedOnly != nil {
+		return *x.DefinedOnly
+	}
+	return false
+}
+
+func (x *FieldRules) GetMinItems() uint64 {
+	if x != nil && x.MinItems != nil {
+		return *x.MinItems
+	}
+	return 0
+}
+
+func (x *FieldRules) GetMaxItems() uint64 {
+	if x != nil && x.MaxItems != nil {
+		return *x.MaxItems
+	}
+	return 0
+}
+
+var file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FieldOptions)(nil),
+		ExtensionType: (*FieldRules)(nil),
+		Field:         50603,
+		Name:          "govalidate.rules",
+		Tag:           "bytes,50603,opt,name=rules",
+		Filename:      "github.com/golang/protobuf/protoc-gen-go/govalidate/govalidate.proto",
+	},
+}
+
+// Extension fields to descriptorpb.FieldOptions.
+var (
+	// optional govalidate.FieldRules rules = 50603;
+	E_Rules = &file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_extTypes[0]
+)
+
+var File_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto protoreflect.FileDescriptor
+
+var file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDesc = []byte{
+	0x0a, 0x44, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c,
+	0x61, 0x6e, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x76, 0x61, 0x6c,
+	0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x67, 0x6f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x67, 0x6f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf5, 0x01, 0x0a, 0x0a, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x75,
+	0x6c, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12,
+	0x17, 0x0a, 0x07, 0x6d, 0x69, 0x6e, 0x5f, 0x6c, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x6d, 0x69, 0x6e, 0x4c, 0x65, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f,
+	0x6c, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x6d, 0x61, 0x78, 0x4c, 0x65,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12,
+	0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x69, 0x6e, 0x65, 0x64, 0x4f, 0x6e,
+	0x6c, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x69, 0x6e, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x04, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x12,
+	0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x3a, 0x4d, 0x0a, 0x05,
+	0x72, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0xab, 0x8b, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67,
+	0x6f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52,
+	0x75, 0x6c, 0x65, 0x73, 0x52, 0x05, 0x72, 0x75, 0x6c, 0x65, 0x73, 0x42, 0x35, 0x5a, 0x33, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x2d, 0x67, 0x65, 0x6e, 0x2d, 0x67, 0x6f, 0x2f, 0x67, 0x6f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x65, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x32,
+}
+
+var (
+	file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescOnce sync.Once
+	file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescData = file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDesc
+)
+
+func file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescGZIP() []byte {
+	file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescOnce.Do(func() {
+		file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescData = protoimpl.X.CompressGZIP(file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescData)
+	})
+	return file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDescData
+}
+
+var file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_goTypes = []interface{}{
+	(*FieldRules)(nil),                // 0: govalidate.FieldRules
+	(*descriptorpb.FieldOptions)(nil), // 1: google.protobuf.FieldOptions
+}
+var file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_depIdxs = []int32{
+	1, // 0: govalidate.rules:extendee -> google.protobuf.FieldOptions
+	0, // 1: govalidate.rules:type_name -> govalidate.FieldRules
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	1, // [1:2] is the sub-list for extension type_name
+	0, // [0:1] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_init() }
+func file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_init() {
+	if File_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FieldRules); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 1,
+			NumServices:   0,
+		},
+		GoTypes:           file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_goTypes,
+		DependencyIndexes: file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_depIdxs,
+		MessageInfos:      file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_msgTypes,
+		ExtensionInfos:    file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_extTypes,
+	}.Build()
+	File_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto = out.File
+	file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_rawDesc = nil
+	file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_goTypes = nil
+	file_github_com_golang_protobuf_protoc_gen_go_govalidate_govalidate_proto_depIdxs = nil
+}
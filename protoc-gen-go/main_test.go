@@ -0,0 +1,41 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/protobuf/compiler/protogen"
+)
+
+func TestRewriteImport(t *testing.T) {
+	const inRepo = "github.com/golang/protobuf/protobuf"
+	for _, tt := range []struct {
+		path                  protogen.GoImportPath
+		prefix, runtimeModule string
+		want                  protogen.GoImportPath
+	}{
+		{"google.golang.org/protobuf/proto", "", "", "google.golang.org/protobuf/proto"},
+		{"google.golang.org/protobuf/proto", "", inRepo, inRepo + "/proto"},
+		{"google.golang.org/protobuf/types/known/timestamppb", "", inRepo + "/", inRepo + "/types/known/timestamppb"},
+		{"google.golang.org/protobuf", "", inRepo, inRepo},
+		{"google.golang.org/protobufx/proto", "", inRepo, "google.golang.org/protobufx/proto"},
+		{"google.golang.org/grpc", "", inRepo, "google.golang.org/grpc"},
+		{"example.com/api/user", "", inRepo, "example.com/api/user"},
+		{"context", "vendor/", "", "context"},
+		{"unicode/utf8", "vendor/", "", "unicode/utf8"},
+		{"net/http", "vendor/", inRepo, "net/http"},
+		{"example.com/api/user", "vendor/", "", "vendor/example.com/api/user"},
+		{"google.golang.org/protobuf/proto", "vendor/", inRepo, "vendor/" + inRepo + "/proto"},
+		{"meta/pkg/kite", "vendor/", "", "vendor/meta/pkg/kite"},
+		{"meta/pkg/kite", "vendor/", inRepo, "vendor/meta/pkg/kite"},
+		{"meta/pkg/kite/pb", "vendor/", "", "vendor/meta/pkg/kite/pb"},
+		{"meta/pkg/kitex", "vendor/", "", "meta/pkg/kitex"},
+	} {
+		if got := rewriteImport(tt.path, tt.prefix, tt.runtimeModule); got != tt.want {
+			t.Errorf("rewriteImport(%q, %q, %q) = %q, want %q", tt.path, tt.prefix, tt.runtimeModule, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package protovalidate provides the errors returned by the Validate methods
+// generated by the validate plugin of protoc-gen-go.
+//
+// The constraints checked by a Validate method are declared with the
+// (govalidate.rules) field option in
+// github.com/golang/protobuf/protoc-gen-go/govalidate/govalidate.proto.
+//
+// This package does not depend on a protobuf runtime, so that it can be used
+// by generated code regardless of the runtime that the code imports.
+package protovalidate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Validator is implemented by messages with a generated Validate method.
+type Validator interface {
+	// Validate reports every field that does not satisfy its constraints.
+	// It returns nil or an Error.
+	Validate() error
+}
+
+// Violation describes a field that does not satisfy one of its constraints.
+type Violation struct {
+	// Path is the path of the field relative to the validated message,
+	// written in the syntax of protopath.Path without the root step
+	// (e.g., "address.zip_code", "items[2]" or `labels["key"]`).
+	Path string
+	// Rule is the name of the violated rule in govalidate.FieldRules
+	// (e.g., "min_len").
+	Rule string
+	// Message describes the violation.
+	Message string
+}
+
+func (v *Violation) Error() string {
+	return v.Path + ": " + v.Message
+}
+
+// Error is returned by a Validate method and lists every violation
+// in the validated message, including its nested messages.
+type Error []*Violation
+
+func (e Error) Error() string {
+	var ss []string
+	for _, v := range e {
+		ss = append(ss, v.Error())
+	}
+	return strings.Join(ss, "; ")
+}
+
+// Unwrap returns the violations as errors.
+func (e Error) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, v := range e {
+		errs[i] = v
+	}
+	return errs
+}
+
+// Is reports whether any violation matches target.
+// Unlike Unwrap, it is also used by errors.Is before Go 1.20.
+func (e Error) Is(target error) bool {
+	for _, v := range e {
+		if errors.Is(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first violation that matches target and sets target to it.
+// Unlike Unwrap, it is also used by errors.As before Go 1.20.
+func (e Error) As(target interface{}) bool {
+	for _, v := range e {
+		if errors.As(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns e, or nil if it has no violations.
+//
+// It is called by generated code.
+func (e Error) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Add appends a violation of the rule by the field at path.
+//
+// It is called by generated code.
+func (e *Error) Add(path, rule, message string) {
+	*e = append(*e, &Violation{Path: path, Rule: rule, Message: message})
+}
+
+// AddNested validates m if it is a Validator and appends its violations
+// with their paths prefixed by the path of m.
+//
+// It is called by generated code.
+func (e *Error) AddNested(path string, m interface{}) {
+	v, ok := m.(Validator)
+	if !ok {
+		return
+	}
+	err := v.Validate()
+	if err == nil {
+		return
+	}
+	nested, ok := err.(Error)
+	if !ok {
+		e.Add(path, "", err.Error())
+		return
+	}
+	for _, v := range nested {
+		*e = append(*e, &Violation{Path: path + "." + v.Path, Rule: v.Rule, Message: v.Message})
+	}
+}
+
+// IndexPath returns the path of the element of the list at path with index i.
+//
+// It is called by generated code.
+func IndexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// KeyPath returns the path of the entry of the map at path with the given key.
+//
+// It is called by generated code.
+func KeyPath(path string, key interface{}) string {
+	if s, ok := key.(string); ok {
+		return path + "[" + strconv.Quote(s) + "]"
+	}
+	return fmt.Sprintf("%s[%v]", path, key)
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package protovalidate
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// address is a stand-in for a generated message with a Validate method.
+type address struct{ zip string }
+
+func (a *address) Validate() error {
+	if a == nil {
+		return nil
+	}
+	var errs Error
+	if a.zip == "" {
+		errs.Add("zip", "required", "is required")
+	}
+	return errs.Err()
+}
+
+func TestError(t *testing.T) {
+	var errs Error
+	if err := errs.Err(); err != nil {
+		t.Errorf("Err() with no violations = %v, want nil", err)
+	}
+
+	errs.Add("name", "min_len", "must be at least 1 characters long")
+	errs.AddNested("address", &address{})
+	errs.AddNested(IndexPath("others", 2), &address{})
+	errs.AddNested(KeyPath("by_name", "a"), &address{})
+	errs.AddNested(KeyPath("by_id", 7), &address{zip: "z"})
+	errs.AddNested("home", (*address)(nil))
+	errs.AddNested("unvalidated", struct{}{})
+
+	err := errs.Err()
+	want := `name: must be at least 1 characters long; address.zip: is required; others[2].zip: is required; by_name["a"].zip: is required`
+	if err == nil || err.Error() != want {
+		t.Fatalf("Err() = %v, want %v", err, want)
+	}
+	var v *Violation
+	if !errors.As(err, &v) || v.Path != "name" || v.Rule != "min_len" {
+		t.Errorf("errors.As(err, *Violation) = %+v, want the name violation", v)
+	}
+
+	// Is and As find the violations without relying on Unwrap() []error.
+	errs = err.(Error)
+	if !errs.Is(errs[1]) || errs.Is(&Violation{Path: "name"}) {
+		t.Errorf("Error.Is does not match exactly its violations")
+	}
+	v = nil
+	if !errs.As(&v) || v != errs[0] {
+		t.Errorf("Error.As(*Violation) = %+v, want the name violation", v)
+	}
+	var pe *strconv.NumError
+	if errs.As(&pe) {
+		t.Errorf("Error.As(*strconv.NumError) = true, want false")
+	}
+}